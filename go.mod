@@ -1,3 +1,3 @@
-module initgoproject
+module github.com/AlexEkdahl/goinit
 
 go 1.19
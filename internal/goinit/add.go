@@ -0,0 +1,384 @@
+package goinit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// component is a part of a project goinit add creates on its own.
+type component struct {
+	name string
+	// aliases are other names the component is added by.
+	aliases     []string
+	description string
+	// files are the files the component creates, which must not exist.
+	files []string
+	// gitConfig are the repository configuration keys the component sets.
+	gitConfig []string
+	create    func(dir string, opts options) error
+}
+
+var components = []component{
+	{
+		name:        ComponentMakefile,
+		description: "Makefile with build, test and lint targets",
+		files:       []string{Makefile},
+		create: func(dir string, opts options) error {
+			return renderFile(dir, Makefile, templatesFS, MakefileTemplate, newProjectContext(dir, opts))
+		},
+	},
+	{
+		name:        ComponentTaskfile,
+		description: "Taskfile.yml with the build and test tasks of the Makefile, for task",
+		files:       []string{TaskfileFile},
+		create: func(dir string, opts options) error {
+			return renderFile(dir, TaskfileFile, templatesFS, TaskfileTemplate, newProjectContext(dir, opts))
+		},
+	},
+	{
+		name:        ComponentJustfile,
+		description: "justfile with the build and test recipes of the Makefile, for just",
+		files:       []string{JustfileFile},
+		create: func(dir string, opts options) error {
+			return renderFile(dir, JustfileFile, templatesFS, JustfileTemplate, newProjectContext(dir, opts))
+		},
+	},
+	{
+		name:        "golangci",
+		description: "golangci-lint configuration",
+		files:       []string{GolangciFile},
+		create:      createGolangci,
+	},
+	{
+		name:        "gitignore",
+		description: ".gitignore for Go projects",
+		files:       []string{GitignoreFile},
+		create: func(dir string, _ options) error {
+			return createFile(dir, GitignoreFile, templatesFS, GitignoreTemplate)
+		},
+	},
+	{
+		name:        "gitattributes",
+		description: ".gitattributes keeping the scripts' line endings on Windows",
+		files:       []string{GitattributesFile},
+		create: func(dir string, _ options) error {
+			return createFile(dir, GitattributesFile, templatesFS, GitattributesTemplate)
+		},
+	},
+	{
+		name:        "editorconfig",
+		description: ".editorconfig with the indentation of Go, Make, YAML and the scripts",
+		files:       []string{EditorconfigFile},
+		create: func(dir string, _ options) error {
+			return createFile(dir, EditorconfigFile, templatesFS, EditorconfigTemplate)
+		},
+	},
+	{
+		name:        EditorVSCode,
+		description: "VS Code settings running gopls and golangci-lint, and recommended extensions",
+		files:       []string{VSCodeSettingsFile, VSCodeExtensionsFile},
+		create:      createVSCodeSettings,
+	},
+	{
+		name:        "devcontainer",
+		description: "dev container for Codespaces and VS Code Remote with Go and golangci-lint",
+		files:       []string{DevcontainerFile, DevcontainerDockerfile},
+		create:      createDevcontainer,
+	},
+	{
+		name:        "goreleaser",
+		description: "GoReleaser configuration",
+		files:       []string{GoreleaserFile},
+		create: func(dir string, opts options) error {
+			return renderFile(dir, GoreleaserFile, templatesFS, GoreleaserTemplate, newProjectContext(dir, opts))
+		},
+	},
+	{
+		name:        ComponentCI,
+		description: "GitHub Actions CI and release workflows",
+		files:       []string{CIWorkflowFile, ReleaserFile},
+		create:      createGithubAction,
+	},
+	{
+		name:        "workflow",
+		description: "GitHub Actions CI workflow testing and linting pushes and pull requests",
+		files:       []string{CIWorkflowFile},
+		create: func(dir string, opts options) error {
+			return renderFiles(dir, []templateFile{{CIWorkflowFile, CIWorkflowTemplate}}, newProjectContext(dir, opts))
+		},
+	},
+	{
+		name:        CommunityFlag,
+		aliases:     []string{"community"},
+		description: "CODEOWNERS, issue forms, pull request template and CONTRIBUTING.md",
+		files:       communityFiles,
+		create:      createGithubCommunity,
+	},
+	{
+		name:        "gitlab-ci",
+		description: "GitLab CI pipeline testing, linting and releasing pushed tags",
+		files:       []string{GitlabCIFile},
+		create:      createGitlabCI,
+	},
+	{
+		name:        "bitbucket-pipelines",
+		description: "Bitbucket Pipelines testing, linting and building the release archives of pushed tags",
+		files:       []string{BitbucketPipelinesFile},
+		create:      createBitbucketPipelines,
+	},
+	{
+		name:        "circleci",
+		description: "CircleCI configuration testing, linting and releasing pushed tags",
+		files:       []string{CircleCIFile},
+		create:      createCircleCI,
+	},
+	{
+		name:        "dockerfile",
+		aliases:     []string{"docker"},
+		description: "multi-stage Dockerfile building the project's command, and its .dockerignore",
+		files:       []string{Dockerfile, DockerignoreFile},
+		create:      createDockerfile,
+	},
+	{
+		name:        ComponentHooks,
+		aliases:     []string{"precommit", "pre-commit"},
+		description: "pre-commit hook in .githooks, which core.hooksPath points git at",
+		files:       []string{PreCommitHookFile},
+		gitConfig:   []string{"core.hooksPath"},
+		create: func(dir string, _ options) error {
+			return createPreCommitHook(dir)
+		},
+	},
+	{
+		name:        "pre-commit-config",
+		description: "pre-commit framework configuration running gofmt, go vet and golangci-lint",
+		files:       []string{PreCommitConfigFile},
+		create: func(dir string, _ options) error {
+			return createPreCommitConfig(dir)
+		},
+	},
+	{
+		name:        "lefthook",
+		description: "lefthook configuration running gofmt, go vet and golangci-lint",
+		files:       []string{LefthookFile},
+		create: func(dir string, _ options) error {
+			return createLefthookConfig(dir)
+		},
+	},
+	{
+		name:        "scripts",
+		description: "setup and cibuild scripts, for sh and PowerShell",
+		files:       []string{SetupScriptFile, CIBuildScriptFile, SetupPowerShellFile, CIBuildPowerShellFile},
+		create: func(dir string, _ options) error {
+			return createScripts(dir)
+		},
+	},
+	{
+		name:        ComponentReadme,
+		description: "README.md with badges, installation and the Makefile's targets",
+		files:       []string{ReadmeFile},
+		create:      createReadme,
+	},
+	{
+		name:        "license",
+		description: "LICENSE of -license with the current year and your git user name",
+		files:       []string{LicenseFile},
+		create:      createLicense,
+	},
+}
+
+func findComponent(name string) (component, bool) {
+	for _, c := range components {
+		if c.name == name {
+			return c, true
+		}
+
+		for _, alias := range c.aliases {
+			if alias == name {
+				return c, true
+			}
+		}
+	}
+
+	return component{}, false
+}
+
+// add creates the named components in the project in the working
+// directory, refusing to overwrite any of their files unless -force or
+// -skip-existing says what to do with them. The files are added to the
+// project's manifest when it has one, so undo removes them too.
+func add(args []string) error {
+	set := flag.NewFlagSet("add", flag.ContinueOnError)
+	license := set.String("license", "mit", "license of the license component: mit, apache-2.0 or bsd-3-clause")
+	lint := set.String(LintFlag, LintStrict, "preset of the golangci component: strict, standard or minimal")
+	conflicts := conflictFlags(set)
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	policy, err := conflicts()
+	if err != nil {
+		return err
+	}
+
+	if set.NArg() == 0 {
+		return errors.New("usage: goinit add [-license name] [-lint preset] [-force | -skip-existing] <component>..., run goinit list components for the components")
+	}
+
+	// Modules are added to a workspace by path rather than as components.
+	if set.Arg(0) == "module" {
+		if set.NArg() != 2 {
+			return errors.New("usage: goinit add module <path>")
+		}
+
+		return addModule(set.Arg(1))
+	}
+
+	if _, ok := licenseTemplates[*license]; !ok {
+		return fmt.Errorf("unknown license %q, use mit, apache-2.0 or bsd-3-clause", *license)
+	}
+
+	if lintPresets[*lint] == "" {
+		return fmt.Errorf("unknown lint preset %q, use strict, standard or minimal", *lint)
+	}
+
+	var selected []component
+
+	seen := make(map[string]bool)
+	// owners maps the files of the selected components to the component
+	// creating them.
+	owners := make(map[string]string)
+
+	var existing []string
+
+	for _, name := range set.Args() {
+		c, ok := findComponent(name)
+		if !ok {
+			return fmt.Errorf("unknown component %q, run goinit list components for the components", name)
+		}
+
+		if seen[c.name] {
+			continue
+		}
+
+		seen[c.name] = true
+
+		// A component is created whole, so -skip-existing leaves out the
+		// components with a file that exists.
+		var inTheWay []string
+
+		for _, file := range c.files {
+			if owner, ok := owners[file]; ok {
+				return fmt.Errorf("%s and %s both create %s, add one of them", owner, c.name, file)
+			}
+
+			owners[file] = c.name
+
+			if exists(file) {
+				inTheWay = append(inTheWay, file)
+			}
+		}
+
+		if len(inTheWay) > 0 && policy == conflictSkip {
+			fmt.Printf("Skipped %s, %s exists\n", c.name, inTheWay[0])
+			continue
+		}
+
+		existing = append(existing, inTheWay...)
+
+		if len(c.gitConfig) > 0 && !exists(".git") {
+			return fmt.Errorf("%s needs a git repository, run git init first", c.name)
+		}
+
+		selected = append(selected, c)
+	}
+
+	// Every conflict is reported at once, and nothing is added.
+	if len(existing) > 0 && policy == conflictFail {
+		return conflictError(existing)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current working directory: %w", err)
+	}
+
+	opts := options{projectName: filepath.Base(wd), license: *license, lint: *lint}
+
+	opts.module = goModDirective("go.mod", "module")
+	opts.mainPackage = findMainPackage(opts.projectName)
+
+	for _, c := range selected {
+		if err := c.create(wd, opts); err != nil {
+			return fmt.Errorf("error creating %s: %w", c.name, err)
+		}
+
+		fmt.Printf("Added %s\n", strings.Join(c.files, ", "))
+	}
+
+	if !exists(ManifestFile) {
+		return nil
+	}
+
+	return recordComponents(selected)
+}
+
+// recordComponents adds the files and git configuration of the components
+// to the project's manifest.
+func recordComponents(added []component) error {
+	manifest, err := readManifest(ManifestFile)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range added {
+		for _, file := range c.files {
+			sum, err := fileChecksum(file)
+			if err != nil {
+				return fmt.Errorf("error reading %s: %w", file, err)
+			}
+
+			manifest.Files[file] = sum
+		}
+
+		for _, key := range c.gitConfig {
+			if !recordsGitConfig(manifest, key) {
+				manifest.GitConfig = append(manifest.GitConfig, key)
+			}
+		}
+	}
+
+	return writeManifest(ManifestFile, manifest)
+}
+
+func recordsGitConfig(m projectManifest, key string) bool {
+	for _, k := range m.GitConfig {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findMainPackage returns the package path of the command of a project
+// goinit did not generate: cmd/<project>, the only command in cmd, or the
+// module's root.
+func findMainPackage(projectName string) string {
+	if exists(filepath.Join(CmdDir, projectName)) {
+		return "./" + path.Join(CmdDir, projectName)
+	}
+
+	entries, err := os.ReadDir(CmdDir)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return "./" + path.Join(CmdDir, entries[0].Name())
+	}
+
+	return "."
+}
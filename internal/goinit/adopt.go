@@ -0,0 +1,224 @@
+package goinit
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+const HereFlag = "here"
+
+// adoptProject adds the files of the project generated from opts to the
+// existing directory dir, failing on the files it already has unless
+// policy overwrites or keeps them. The go.mod and go.sum of a module that
+// exists are always kept. The project is generated in a temporary
+// directory and the files are copied over.
+func adoptProject(dir string, opts options, policy conflictPolicy) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", dir, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	hadModule := exists(filepath.Join(dir, "go.mod"))
+
+	// The templates refer to the module the directory already declares.
+	if opts.module == "" && hadModule {
+		opts.module = goModDirective(filepath.Join(dir, "go.mod"), "module")
+	}
+
+	tmp, err := os.MkdirTemp("", "goinit-adopt-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := generateIn(rootCtx, tmp, opts); err != nil {
+		return err
+	}
+
+	generated := filepath.Join(tmp, opts.projectName)
+
+	names, err := walkFiles(generated)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(names))
+	for name := range names {
+		paths = append(paths, name)
+	}
+
+	sort.Strings(paths)
+
+	// A module that exists has code of its own, which the starter command
+	// would only be in the way of, and its own requirements.
+	kept := make(map[string]bool)
+	if hadModule {
+		kept["go.mod"], kept["go.sum"] = true, true
+	}
+
+	if hadModule && !opts.hasMain() {
+		for _, file := range starterFiles(opts.projectName) {
+			kept[filepath.ToSlash(file.Name)] = true
+		}
+	}
+
+	var conflicts []string
+
+	for _, path := range paths {
+		if !kept[path] && exists(filepath.Join(dir, filepath.FromSlash(path))) {
+			conflicts = append(conflicts, path)
+		}
+	}
+
+	if len(conflicts) > 0 && policy == conflictFail {
+		return conflictError(conflicts)
+	}
+
+	added := make(map[string]string)
+
+	for _, path := range paths {
+		if kept[path] {
+			continue
+		}
+
+		dst := filepath.Join(dir, filepath.FromSlash(path))
+		if exists(dst) && policy == conflictSkip {
+			fmt.Printf("Skipped %s, it exists\n", path)
+			continue
+		}
+
+		if err := copyFile(filepath.Join(generated, filepath.FromSlash(path)), dst); err != nil {
+			return err
+		}
+
+		if added[path], err = fileChecksum(dst); err != nil {
+			return fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		fmt.Printf("Added %s\n", path)
+	}
+
+	createdRepository := !opts.noGit && !exists(filepath.Join(dir, ".git"))
+	if createdRepository {
+		if err := runCommand(dir, "git", "init"); err != nil {
+			return fmt.Errorf("error initializing repository: %w", err)
+		}
+	}
+
+	// Windows has no executable bit to copy, so the scripts are marked
+	// executable in the index, as generation does.
+	if runtime.GOOS == "windows" && !opts.noGit {
+		if err := markExecutables(generated, dir, added); err != nil {
+			return err
+		}
+	}
+
+	var gitConfig []string
+
+	if _, ok := added[PreCommitHookFile]; ok {
+		if err := runCommand(dir, "git", "config", "core.hooksPath", GitHooksDir); err != nil {
+			return fmt.Errorf("error setting core.hooksPath: %w", err)
+		}
+
+		gitConfig = append(gitConfig, "core.hooksPath")
+	}
+
+	if hadModule && opts.hasDependencies() && len(added) > 0 {
+		log.Printf("Run `go mod tidy` in %s to add the modules the added code imports", dir)
+	}
+
+	return adoptManifest(filepath.Join(dir, ManifestFile), opts, createdRepository, gitConfig, added)
+}
+
+// adoptManifest records the added files and git configuration, in the
+// directory's manifest when it already has one.
+func adoptManifest(name string, opts options, createdRepository bool, gitConfig []string, added map[string]string) error {
+	manifest := projectManifest{
+		Version:           currentVersion(),
+		Name:              opts.projectName,
+		Conventions:       len(migrations),
+		CreatedRepository: createdRepository,
+		Module:            opts.modulePath(),
+		GoVersion:         goModDirective(filepath.Join(filepath.Dir(name), "go.mod"), "go"),
+		Template:          templatesSource.source,
+		TemplateRevision:  templatesSource.revision,
+		Options:           opts.args,
+		Files:             make(map[string]string),
+	}
+
+	if exists(name) {
+		var err error
+		if manifest, err = readManifest(name); err != nil {
+			return err
+		}
+	}
+
+	for path, sum := range added {
+		manifest.Files[path] = sum
+	}
+
+	for _, key := range gitConfig {
+		if !recordsGitConfig(manifest, key) {
+			manifest.GitConfig = append(manifest.GitConfig, key)
+		}
+	}
+
+	return writeManifest(name, manifest)
+}
+
+// markExecutables marks the files of added that are executable in the
+// repository generated as executable in the index of dir.
+func markExecutables(generated, dir string, added map[string]string) error {
+	out, err := commandOutput(generated, "git", "ls-files", "--stage")
+	if err != nil {
+		return fmt.Errorf("error listing the executable files: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		meta, path, ok := strings.Cut(line, "\t")
+		if _, added := added[path]; !ok || !added || !strings.HasPrefix(meta, "100755 ") {
+			continue
+		}
+
+		if err := runCommand(dir, "git", "add", "--chmod=+x", path); err != nil {
+			return fmt.Errorf("error making %s executable in git: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// copyFile copies src to dst with its permissions, creating the directories
+// of dst.
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", src, err)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", src, err)
+	}
+
+	if err := ensureDir(filepath.Dir(dst)); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(dst, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("error writing %s: %w", dst, err)
+	}
+
+	logs.file(dst)
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package goinit
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	AnswersFlag = "answers"
+	// ProjectNameAnswer names the project in answers, more readable than
+	// the d of its flag.
+	ProjectNameAnswer = "name"
+)
+
+// answer is an option value from an answers file.
+type answer struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// readAnswers reads an answers file: a YAML mapping of option names, as the
+// flags are named without their dash, to values. Template variables are
+// nested under set.
+//
+//	name: payments
+//	layout: cronjob
+//	cors: true
+//	set:
+//	  team: payments
+func readAnswers(name string) ([]answer, error) {
+	lines, err := readLines(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading answers: %w", err)
+	}
+
+	return parseAnswers(name, lines)
+}
+
+// sourceLine is a line of a file, with its number for error messages.
+type sourceLine struct {
+	Number int
+	Text   string
+}
+
+// readLines returns the lines of name that are not blank, comments or
+// document markers, without trailing whitespace.
+func readLines(name string) ([]sourceLine, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []sourceLine
+
+	scanner := bufio.NewScanner(f)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if trimmed := strings.TrimSpace(text); trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+
+		lines = append(lines, sourceLine{Number: number, Text: text})
+	}
+
+	return lines, scanner.Err()
+}
+
+func parseAnswers(name string, lines []sourceLine) ([]answer, error) {
+	var (
+		answers []answer
+		section string
+	)
+
+	for _, l := range lines {
+		line, text := l.Number, l.Text
+
+		nested := text[0] == ' ' || text[0] == '\t'
+		if nested && section != SetFlag {
+			return nil, fmt.Errorf("%s:%d: only template variables under %s can be nested", name, line, SetFlag)
+		}
+
+		key, raw, ok := strings.Cut(strings.TrimSpace(text), ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected `option: value`", name, line)
+		}
+
+		key, raw = strings.TrimSpace(key), strings.TrimSpace(raw)
+
+		if !nested && key == SetFlag && raw == "" {
+			section = SetFlag
+			continue
+		}
+
+		value, err := answerValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+
+		if nested {
+			answers = append(answers, answer{Key: SetFlag, Value: key + "=" + value, Line: line})
+			continue
+		}
+
+		section = ""
+		answers = append(answers, answer{Key: key, Value: value, Line: line})
+	}
+
+	return answers, nil
+}
+
+// answerValue parses a double or single quoted or a plain YAML scalar.
+func answerValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		quoted, err := strconv.QuotedPrefix(raw)
+		if err != nil {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+
+		return strconv.Unquote(quoted)
+	case strings.HasPrefix(raw, "'"):
+		var b strings.Builder
+
+		for i := 1; i < len(raw); i++ {
+			if raw[i] != '\'' {
+				b.WriteByte(raw[i])
+				continue
+			}
+
+			// A quote is escaped by doubling it.
+			if i+1 < len(raw) && raw[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+
+				continue
+			}
+
+			return b.String(), nil
+		}
+
+		return "", fmt.Errorf("unterminated string %s", raw)
+	}
+
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+
+	if raw == "~" || raw == "null" {
+		return "", nil
+	}
+
+	return raw, nil
+}
+
+// applyAnswers sets the options of fs from an answers file. Options and
+// template variables given on the command line take precedence.
+func applyAnswers(fs *flag.FlagSet, name string) error {
+	answers, err := readAnswers(name)
+	if err != nil {
+		return err
+	}
+
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	return setAnswers(fs, name, answers, given)
+}
+
+// setAnswers sets the options of fs, leaving out the ones in keep.
+func setAnswers(fs *flag.FlagSet, name string, answers []answer, keep map[string]bool) error {
+	for _, a := range answers {
+		if a.Key == ProjectNameAnswer {
+			a.Key = ProjectNameFlag
+		}
+
+		if runFlags[a.Key] || fs.Lookup(a.Key) == nil {
+			return fmt.Errorf("%s:%d: unknown option %q", name, a.Line, a.Key)
+		}
+
+		if a.Key == SetFlag {
+			vars := fs.Lookup(SetFlag).Value.(varsFlag)
+
+			key, _, _ := strings.Cut(a.Value, "=")
+			if _, set := vars[key]; set && keep[SetFlag] {
+				continue
+			}
+		} else if keep[a.Key] {
+			continue
+		}
+
+		if err := fs.Set(a.Key, a.Value); err != nil {
+			return fmt.Errorf("%s:%d: invalid value %q for %s: %w", name, a.Line, a.Value, a.Key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package goinit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAnswers(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "answers.yaml")
+
+	content := `---
+# The payments service.
+name: payments
+layout: cronjob   # runs nightly
+cors: true
+module: "example.com/team/payments"
+license: 'it''s mit'
+host: ~
+
+set:
+  team: payments
+  owner: "Ops # team"
+ci: gitlab
+`
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readAnswers(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []answer{
+		{Key: "name", Value: "payments", Line: 3},
+		{Key: "layout", Value: "cronjob", Line: 4},
+		{Key: "cors", Value: "true", Line: 5},
+		{Key: "module", Value: "example.com/team/payments", Line: 6},
+		{Key: "license", Value: "it's mit", Line: 7},
+		{Key: "host", Value: "", Line: 8},
+		{Key: SetFlag, Value: "team=payments", Line: 11},
+		{Key: SetFlag, Value: "owner=Ops # team", Line: 12},
+		{Key: "ci", Value: "gitlab", Line: 13},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readAnswers = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseAnswersErrors(t *testing.T) {
+	tests := []struct {
+		lines []sourceLine
+		err   string
+	}{
+		{[]sourceLine{{1, "name: payments"}, {2, "  team: payments"}}, "answers.yaml:2: only template variables under set can be nested"},
+		{[]sourceLine{{1, "name payments"}}, "answers.yaml:1: expected `option: value`"},
+		{[]sourceLine{{4, `name: "payments`}}, "answers.yaml:4: unterminated string"},
+		{[]sourceLine{{4, `name: 'payments`}}, "answers.yaml:4: unterminated string"},
+		{[]sourceLine{{1, "set:"}, {2, "  team: payments"}, {3, "ci: gitlab"}, {4, "  owner: ops"}}, "answers.yaml:4: only template variables"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseAnswers("answers.yaml", tt.lines)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parseAnswers(%v) = %v, want an error containing %q", tt.lines, err, tt.err)
+		}
+	}
+}
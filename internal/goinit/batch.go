@@ -0,0 +1,248 @@
+package goinit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// batchProject is a project of a batch spec with its options resolved.
+type batchProject struct {
+	opts options
+	line int
+}
+
+// batch generates every project of a spec file, for example a set of
+// services or one project per workshop attendee.
+func batch(args []string) error {
+	set := flag.NewFlagSet("batch", flag.ContinueOnError)
+	dir := set.String("dir", ".", "directory to create the projects in")
+	keepGoing := set.Bool("keep-going", false, "generate the remaining projects after one fails")
+	noColor := set.Bool(NoColorFlag, false, "disable colored output")
+	setupOutput := outputFlags(set)
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	if set.NArg() != 1 {
+		return errors.New("usage: goinit batch [-dir dir] [-keep-going] projects.yaml")
+	}
+
+	spec := set.Arg(0)
+
+	projects, err := readBatch(spec)
+	if err != nil {
+		return err
+	}
+
+	// Every project is checked before any is generated, so a mistake in
+	// the spec does not leave half of the batch behind.
+	seen := make(map[string]int)
+
+	for _, p := range projects {
+		name := p.opts.projectName
+		if line, ok := seen[name]; ok {
+			return fmt.Errorf("%s:%d: project %s is already defined on line %d", spec, p.line, name, line)
+		}
+
+		seen[name] = p.line
+
+		if exists(filepath.Join(*dir, name)) {
+			return fmt.Errorf("%s:%d: folder %s already exists", spec, p.line, filepath.Join(*dir, name))
+		}
+	}
+
+	if err := setupOutput(*noColor); err != nil {
+		return err
+	}
+
+	defer handleInterrupts()()
+
+	failed := 0
+
+	for i, p := range projects {
+		fmt.Fprintf(steps, "[%d/%d] %s\n", i+1, len(projects), p.opts.projectName)
+
+		if err := generateIn(rootCtx, *dir, p.opts); err != nil {
+			steps.fail()
+
+			if !*keepGoing || errors.Is(err, errInterrupted) {
+				return fmt.Errorf("error creating %s: %w", p.opts.projectName, err)
+			}
+
+			log.Printf("Error creating %s: %v", p.opts.projectName, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d projects failed", failed, len(projects))
+	}
+
+	return nil
+}
+
+// readBatch reads a batch spec: the projects to generate, each a mapping
+// of options as in an answers file, and defaults applied to all of them.
+//
+//	defaults:
+//	  cors: true
+//	projects:
+//	  - name: orders
+//	    layout: cronjob
+//	  - name: billing
+//	    set:
+//	      team: payments
+func readBatch(name string) ([]batchProject, error) {
+	lines, err := readLines(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading batch spec: %w", err)
+	}
+
+	var (
+		defaults []answer
+		projects []batchProject
+		section  string
+		block    []sourceLine
+		indent   int
+		inItem   bool
+	)
+
+	// flush ends the defaults or the project being read.
+	flush := func() error {
+		if len(block) == 0 {
+			return nil
+		}
+
+		answers, err := parseAnswers(name, block)
+		if err != nil {
+			return err
+		}
+
+		if section == "defaults" {
+			defaults = append(defaults, answers...)
+		} else {
+			p, err := newBatchProject(name, block[0].Number, defaults, answers)
+			if err != nil {
+				return err
+			}
+
+			projects = append(projects, p)
+		}
+
+		block = nil
+
+		return nil
+	}
+
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimLeft(l.Text, " "), "\t") {
+			return nil, fmt.Errorf("%s:%d: indent with spaces, not tabs", name, l.Number)
+		}
+
+		if l.Text[0] != ' ' {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			key, rest, _ := strings.Cut(l.Text, ":")
+			if (key != "defaults" && key != "projects") || strings.TrimSpace(rest) != "" {
+				return nil, fmt.Errorf("%s:%d: expected defaults: or projects:", name, l.Number)
+			}
+
+			if key == "defaults" && len(projects) > 0 {
+				return nil, fmt.Errorf("%s:%d: defaults have to come before the projects", name, l.Number)
+			}
+
+			section, inItem = key, false
+
+			continue
+		}
+
+		text := l.Text
+		trimmed := strings.TrimLeft(text, " ")
+		lead := len(text) - len(trimmed)
+
+		if section == "projects" && (trimmed == "-" || strings.HasPrefix(trimmed, "- ")) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			// The item's keys line up with the first one, after the dash.
+			indent, inItem = lead+2, true
+			if trimmed == "-" {
+				continue
+			}
+
+			text = strings.Repeat(" ", indent) + strings.TrimLeft(trimmed[1:], " ")
+			lead = indent
+		} else if len(block) == 0 && !inItem {
+			if section != "defaults" {
+				return nil, fmt.Errorf("%s:%d: expected a project starting with -", name, l.Number)
+			}
+
+			indent = lead
+		}
+
+		if lead < indent {
+			return nil, fmt.Errorf("%s:%d: inconsistent indentation", name, l.Number)
+		}
+
+		block = append(block, sourceLine{Number: l.Number, Text: text[indent:]})
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("%s: no projects to generate", name)
+	}
+
+	return projects, nil
+}
+
+// newBatchProject resolves the options of a project from the defaults and
+// its own answers, which take precedence.
+func newBatchProject(spec string, line int, defaults, answers []answer) (batchProject, error) {
+	p := batchProject{line: line}
+
+	set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	registerFlags(set, &p.opts)
+
+	if err := setAnswers(set, userConfig.Path, userConfig.Options, nil); err != nil {
+		return p, err
+	}
+
+	if err := setAnswers(set, spec, defaults, nil); err != nil {
+		return p, err
+	}
+
+	named := false
+
+	for _, a := range answers {
+		named = named || a.Key == ProjectNameAnswer || a.Key == ProjectNameFlag
+	}
+
+	if !named {
+		return p, fmt.Errorf("%s:%d: the project has no name", spec, line)
+	}
+
+	if err := setAnswers(set, spec, answers, nil); err != nil {
+		return p, err
+	}
+
+	p.opts.args = changedArgs(set)
+
+	if err := p.opts.validate(); err != nil {
+		return p, fmt.Errorf("%s:%d: %w", spec, line, err)
+	}
+
+	return p, nil
+}
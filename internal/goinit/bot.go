@@ -0,0 +1,64 @@
+package goinit
+
+const (
+	BotCommandsTemplate     = "templates/bot/commands.go.tmpl"
+	BotCommandsTestTemplate = "templates/bot/commands_internal_test.go.tmpl"
+	SlackTemplate           = "templates/bot/slack.go.tmpl"
+	SlackTestTemplate       = "templates/bot/slack_internal_test.go.tmpl"
+	SlackMainTemplate       = "templates/bot/slack_main.go.tmpl"
+	DiscordTemplate         = "templates/bot/discord.go.tmpl"
+	DiscordTestTemplate     = "templates/bot/discord_internal_test.go.tmpl"
+	DiscordMainTemplate     = "templates/bot/discord_main.go.tmpl"
+	DiscordMakefileTemplate = "templates/bot/discord.mk"
+	BotCommandsFile         = "internal/bot/commands.go"
+	BotCommandsTestFile     = "internal/bot/commands_internal_test.go"
+	SlackFile               = "internal/slack/slack.go"
+	SlackTestFile           = "internal/slack/slack_internal_test.go"
+	DiscordFile             = "internal/discord/discord.go"
+	DiscordTestFile         = "internal/discord/discord_internal_test.go"
+	PlatformSlack           = "slack"
+	PlatformDiscord         = "discord"
+)
+
+// createBotLayout generates a chat bot for -platform: the platform's
+// request verification and command dispatch, an example ping command and
+// a Dockerfile to deploy it with.
+func createBotLayout(dir string, opts options) error {
+	files := []templateFile{
+		{BotCommandsFile, BotCommandsTemplate},
+		{BotCommandsTestFile, BotCommandsTestTemplate},
+	}
+
+	if opts.platform == PlatformSlack {
+		files = append(files,
+			templateFile{SlackFile, SlackTemplate},
+			templateFile{SlackTestFile, SlackTestTemplate},
+			templateFile{MainFile, SlackMainTemplate},
+		)
+	} else {
+		files = append(files,
+			templateFile{DiscordFile, DiscordTemplate},
+			templateFile{DiscordTestFile, DiscordTestTemplate},
+			templateFile{MainFile, DiscordMainTemplate},
+		)
+	}
+
+	if err := renderFiles(dir, files, newPackageInfo(dir, opts)); err != nil {
+		return err
+	}
+
+	// -buildx or -docker already created the Dockerfile.
+	if !opts.hasDockerfile() {
+		if err := createDockerfile(dir, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.platform == PlatformDiscord {
+		if err := appendTargets(dir, TaskRunnerMake, DiscordMakefileTemplate, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
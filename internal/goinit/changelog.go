@@ -0,0 +1,51 @@
+package goinit
+
+import "fmt"
+
+const (
+	ChangelogFlag                   = "changelog"
+	ChangelogReleasePlease          = "release-please"
+	ChangelogGitCliff               = "git-cliff"
+	ChangelogNone                   = "none"
+	ReleasePleaseConfigTemplate     = "templates/changelog/release-please-config.json.tmpl"
+	ReleasePleaseManifestTemplate   = "templates/changelog/release-please-manifest.json"
+	ReleasePleaseGoreleaserTemplate = "templates/changelog/release-please.goreleaser.yml"
+	CliffConfigTemplate             = "templates/changelog/cliff.toml"
+	ReleasePleaseConfigFile         = "release-please-config.json"
+	ReleasePleaseManifestFile       = ".release-please-manifest.json"
+	CliffConfigFile                 = "cliff.toml"
+)
+
+// changelogs maps the -changelog values to the function generating the
+// changelog tool's configuration. The release workflow runs the tool: the
+// releaser runs release-please on pushes to main and GoReleaser once its
+// release pull request is merged, or git-cliff writes the notes GoReleaser
+// publishes on tags.
+var changelogs = map[string]func(dir string, opts options) error{
+	ChangelogReleasePlease: createReleasePlease,
+	ChangelogGitCliff: func(dir string, _ options) error {
+		return createFile(dir, CliffConfigFile, templatesFS, CliffConfigTemplate)
+	},
+	ChangelogNone: func(string, options) error { return nil },
+}
+
+// createReleasePlease writes the release-please configuration of a Go
+// module versioned from 0.0.0, and leaves the release notes to it.
+func createReleasePlease(dir string, opts options) error {
+	err := renderFiles(dir, []templateFile{
+		{ReleasePleaseConfigFile, ReleasePleaseConfigTemplate},
+	}, newProjectContext(dir, opts))
+	if err != nil {
+		return err
+	}
+
+	if err := createFile(dir, ReleasePleaseManifestFile, templatesFS, ReleasePleaseManifestTemplate); err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GoreleaserFile, templatesFS, ReleasePleaseGoreleaserTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GoreleaserFile, err)
+	}
+
+	return nil
+}
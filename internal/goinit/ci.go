@@ -0,0 +1,63 @@
+package goinit
+
+const (
+	CIFlag                     = "ci"
+	CIGithub                   = "github"
+	CIGitlab                   = "gitlab"
+	CIBitbucket                = "bitbucket"
+	CICircleCI                 = "circleci"
+	CINone                     = "none"
+	GitlabCIFile               = ".gitlab-ci.yml"
+	GitlabCITemplate           = "templates/gitlab/gitlab-ci.yml.tmpl"
+	BitbucketPipelinesFile     = "bitbucket-pipelines.yml"
+	BitbucketPipelinesTemplate = "templates/bitbucket/bitbucket-pipelines.yml.tmpl"
+	CircleCIFile               = ".circleci/config.yml"
+	CircleCITemplate           = "templates/circleci/config.yml.tmpl"
+)
+
+// ciProviders maps the supported -ci values to the function generating the
+// provider's CI and release configuration.
+var ciProviders = map[string]func(dir string, opts options) error{
+	CIGithub:    createGithubAction,
+	CIGitlab:    createGitlabCI,
+	CIBitbucket: createBitbucketPipelines,
+	CICircleCI:  createCircleCI,
+	CINone:      func(string, options) error { return nil },
+}
+
+// ciProvider returns the -ci provider, none when -skip ci leaves the CI
+// configuration out. Without -ci it is the provider of the forge hosting
+// the project, or github for an unknown host.
+func (o options) ciProvider() string {
+	switch {
+	case o.skips(ComponentCI):
+		return CINone
+	case o.ci != "":
+		return o.ci
+	}
+
+	if p, ok := forgeCIProviders[forgeName(o.forgeHost())]; ok {
+		return p
+	}
+
+	return CIGithub
+}
+
+// createGitlabCI generates a pipeline testing and linting the project and
+// releasing pushed tags to GitLab with GoReleaser.
+func createGitlabCI(dir string, opts options) error {
+	return renderFiles(dir, []templateFile{{GitlabCIFile, GitlabCITemplate}}, newProjectContext(dir, opts))
+}
+
+// createBitbucketPipelines generates pipelines testing and linting the
+// project, and building the release archives of pushed tags with
+// GoReleaser, which cannot publish them to Bitbucket.
+func createBitbucketPipelines(dir string, opts options) error {
+	return renderFiles(dir, []templateFile{{BitbucketPipelinesFile, BitbucketPipelinesTemplate}}, newProjectContext(dir, opts))
+}
+
+// createCircleCI generates a CircleCI configuration testing and linting
+// the project and releasing pushed tags to GitHub with GoReleaser.
+func createCircleCI(dir string, opts options) error {
+	return renderFiles(dir, []templateFile{{CircleCIFile, CircleCITemplate}}, newProjectContext(dir, opts))
+}
@@ -0,0 +1,326 @@
+package goinit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// subcommand is a goinit command, run as `goinit <name> args...`.
+type subcommand struct {
+	name  string
+	usage string
+	short string
+	// failure prefixes the error the command fails with.
+	failure string
+	run     func(args []string) error
+}
+
+var subcommands = []subcommand{
+	{"new", "new [name] [flags]", "generate a project, the default command", "", newProject},
+	{"add", "add [-license name] [-lint preset] [-force | -skip-existing] <component>...", "add components, or a module to the workspace, in the working directory", "Error adding components: ", add},
+	{"list", "list templates | components", "list the embedded templates or the components of add", "", list},
+	{"config", "config", "print the settings goinit reads from your environment", "", showConfig},
+	{"batch", "batch [flags] spec.yaml", "generate every project of a spec file", "Error generating the batch: ", batch},
+	{"serve", "serve [-addr address]", "serve a web UI for the options", "Error serving web UI: ", serve},
+	{"ui", "ui [name]", "choose the components in a terminal UI previewing the files", "Error running the terminal UI: ", ui},
+	{"diff", "diff [-U n]", "compare the project with its template", "Error comparing the project with its template: ", projectDiff},
+	{"migrate", "migrate [-dry-run]", "bring the project to the current conventions", "Error migrating the project: ", migrate},
+	{"upgrade", "upgrade [-dry-run]", "update the generated files to the current templates", "Error upgrading the project: ", upgrade},
+	{"undo", "undo [-force]", "remove what goinit created in the project", "Error undoing the generation: ", undo},
+	{"templates", "templates test [flags]", "compare the templates with their golden snapshots", "", templatesCommand},
+	{"telemetry", "telemetry on | off | status", "turn the opt-in usage telemetry on or off", "", telemetry},
+	{"self-update", "self-update [-check] [-insecure-skip-signature]", "update goinit to the latest release", "Error updating goinit: ", selfUpdate},
+}
+
+func findSubcommand(name string) (subcommand, bool) {
+	for _, c := range subcommands {
+		if c.name == name {
+			return c, true
+		}
+	}
+
+	return subcommand{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: goinit <command> [arguments]\n\nCommands:\n")
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range subcommands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.usage, c.short)
+	}
+
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nWithout a command, goinit runs new with the flags given. Run goinit new -h for the flags.\n")
+}
+
+// newProject generates a project. The name is the first argument, before or
+// after the flags, or -d.
+func newProject(args []string) error {
+	var opts options
+
+	set := flag.NewFlagSet("new", flag.ContinueOnError)
+	registerFlags(set, &opts)
+	noColor := set.Bool(NoColorFlag, false, "disable colored output")
+	set.DurationVar(&commandTimeout, TimeoutFlag, DefaultCommandTimeout, "time limit of each git and go command")
+	set.DurationVar(&networkTimeout, NetworkTimeoutFlag, DefaultNetworkTimeout, "time limit of each download, such as go mod tidy")
+	answers := set.String(AnswersFlag, "", "read option values from a YAML answers file")
+	interactive := set.Bool(InteractiveFlag, false, "ask for the project name, module path, license and components")
+	set.BoolVar(&keepPartial, KeepPartialFlag, false, "keep the partly generated project when generation fails")
+	here := set.Bool(HereFlag, false, "add the files of the project to an existing directory, the working directory unless named")
+	conflicts := conflictFlags(set)
+	templates := set.String(TemplateFlag, "", "directory or git repository of templates overriding the embedded ones")
+	setupOutput := outputFlags(set)
+	dry := set.Bool(DryRunFlag, false, "print the files and commands of the project without creating it")
+
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "usage: goinit new [name] [flags]\n\nFlags:\n")
+		set.PrintDefaults()
+	}
+
+	// Run without arguments on a terminal, goinit asks rather than
+	// generating new_project.
+	ask := len(args) == 0 && isTerminal(os.Stdin) && isTerminal(os.Stderr)
+
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	if set.NArg() > 0 {
+		if name != "" || set.NArg() > 1 {
+			return fmt.Errorf("unexpected arguments %q, give the project name once", set.Args())
+		}
+
+		name = set.Arg(0)
+	}
+
+	if name != "" {
+		if err := set.Set(ProjectNameFlag, name); err != nil {
+			return err
+		}
+	}
+
+	if *answers != "" {
+		if err := applyAnswers(set, *answers); err != nil {
+			return err
+		}
+	}
+
+	if err := applyUserConfig(set); err != nil {
+		return err
+	}
+
+	if *interactive || ask {
+		if err := runWizard(os.Stdin, os.Stderr, set, &opts); err != nil {
+			return err
+		}
+	}
+
+	// The directory adopted is the one named, and its name is the project's.
+	adopted := "."
+
+	if *here {
+		if isSet(set, ProjectNameFlag) {
+			adopted = opts.projectName
+		}
+
+		abs, err := filepath.Abs(adopted)
+		if err != nil {
+			return fmt.Errorf("error resolving %s: %w", adopted, err)
+		}
+
+		opts.projectName = filepath.Base(abs)
+	}
+
+	opts.args = changedArgs(set)
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	if err := setupOutput(*noColor); err != nil {
+		return err
+	}
+
+	warnUppercase(opts)
+
+	defer handleInterrupts()()
+
+	if *dry && *here {
+		return errors.New("-dry-run cannot be combined with -here")
+	}
+
+	// The temporary project -here copies from is the one generation
+	// commits, and the directory has changes of its own to commit.
+	if opts.commit && *here {
+		return fmt.Errorf("-%s cannot be combined with -here, commit the added files yourself", CommitFlag)
+	}
+
+	// They would set up a repository for the temporary project too.
+	if (opts.createRemote || opts.push || opts.labels || opts.protect) && *here {
+		return errors.New("-create-remote, -push, -labels and -protect set up the repository of a new project, which -here does not create")
+	}
+
+	policy, err := conflicts()
+	if err != nil {
+		return err
+	}
+
+	if policy != conflictFail && !*here {
+		return fmt.Errorf("-%s and -%s apply to the existing files -here adds to", ForceFlag, SkipExistingFlag)
+	}
+
+	restore, err := useTemplates(*templates)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if *here {
+		if err := adoptProject(adopted, opts, policy); err != nil {
+			steps.fail()
+			return fmt.Errorf("error adding the project's files to %s: %w", adopted, err)
+		}
+
+		recordUsage(set)
+
+		return nil
+	}
+
+	if *dry {
+		if err := dryRun(os.Stdout, opts); err != nil {
+			return fmt.Errorf("error planning project: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := generateProject(".", opts); err != nil {
+		steps.fail()
+		return fmt.Errorf("error creating project: %w", err)
+	}
+
+	logs.summary(".", opts)
+	recordUsage(set)
+
+	return nil
+}
+
+// isSet reports whether the flag name was given.
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+
+	fs.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+
+	return set
+}
+
+// list prints the embedded templates or the components of goinit add.
+func list(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: goinit list templates | components")
+	}
+
+	switch args[0] {
+	case "templates":
+		var names []string
+
+		err := fs.WalkDir(templatesFS, "templates", func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				names = append(names, strings.TrimPrefix(path, "templates/"))
+			}
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error listing templates: %w", err)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	case "components":
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, c := range components {
+			name := c.name
+			if len(c.aliases) > 0 {
+				name += " (" + strings.Join(c.aliases, ", ") + ")"
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\n", name, c.description)
+		}
+
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown list %q, use templates or components", args[0])
+	}
+
+	return nil
+}
+
+// showConfig prints the settings goinit reads from the environment and
+// uses as defaults.
+func showConfig(args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: goinit config")
+	}
+
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	settings, err := loadTelemetrySettings()
+	if err != nil {
+		return err
+	}
+
+	telemetry := "off"
+	if settings.Enabled {
+		telemetry = "on"
+	}
+
+	goVersion := "not installed"
+	if out, err := commandOutput("", "go", "env", "GOVERSION"); err == nil {
+		goVersion = strings.TrimSpace(string(out))
+	}
+
+	file := userConfig.Path
+	if !exists(file) {
+		file += " (not found)"
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "version\t%s\n", currentVersion())
+	fmt.Fprintf(tw, "config dir\t%s\n", dir)
+	fmt.Fprintf(tw, "config file\t%s\n", file)
+	fmt.Fprintf(tw, "telemetry\t%s\n", telemetry)
+	prefix, source := modulePrefix("")
+	fmt.Fprintf(tw, "module prefix\t%s (%s)\n", prefix, source)
+	fmt.Fprintf(tw, "author\t%s\n", gitMaintainer(""))
+	fmt.Fprintf(tw, "go\t%s\n", goVersion)
+
+	for _, a := range userConfig.Options {
+		fmt.Fprintf(tw, "default -%s\t%s\n", a.Key, a.Value)
+	}
+
+	return tw.Flush()
+}
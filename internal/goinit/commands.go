@@ -0,0 +1,132 @@
+package goinit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultCommandTimeout = 2 * time.Minute
+	DefaultNetworkTimeout = 10 * time.Minute
+	TimeoutFlag           = "timeout"
+	NetworkTimeoutFlag    = "network-timeout"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+var (
+	// rootCtx is cancelled when goinit is interrupted, which stops the
+	// command running under it.
+	rootCtx = context.Background()
+	// commandTimeout bounds the local git and go commands, networkTimeout
+	// the ones that download modules or talk to a server.
+	commandTimeout = DefaultCommandTimeout
+	networkTimeout = DefaultNetworkTimeout
+)
+
+// handleInterrupts cancels rootCtx on the first interrupt, while a second
+// one stops goinit right away. Call the returned function to stop handling
+// them.
+func handleInterrupts() func() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	rootCtx = ctx
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return stop
+}
+
+// command runs name under the root context for at most timeout. The
+// returned run function reports a timeout or interruption as such.
+func command(timeout time.Duration, name string, arg ...string) (*exec.Cmd, func(func() error) error) {
+	ctx, cancel := context.WithTimeout(rootCtx, timeout)
+	cmd := exec.CommandContext(ctx, name, arg...)
+
+	run := func(fn func() error) error {
+		defer cancel()
+
+		err := fn()
+
+		switch {
+		case err == nil:
+			return nil
+		case rootCtx.Err() != nil:
+			return errInterrupted
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return fmt.Errorf("%s timed out after %s", name, timeout)
+		}
+
+		return err
+	}
+
+	return cmd, run
+}
+
+// plannedCommands records the commands that change the project during a
+// dry run, which leaves out the ones that download.
+var plannedCommands *[]string
+
+// runCommand runs name in dir, the working directory when dir is empty.
+func runCommand(dir, name string, arg ...string) error {
+	planCommand("", name, arg)
+	logs.command(name, arg)
+
+	cmd, run := command(commandTimeout, name, arg...)
+	cmd.Dir = dir
+
+	return run(cmd.Run)
+}
+
+// runNetworkCommand runs a command that downloads, such as go mod tidy, in
+// dir.
+func runNetworkCommand(dir, name string, arg ...string) error {
+	if plannedCommands != nil {
+		planCommand("skipped in the dry run, it downloads", name, arg)
+		return nil
+	}
+
+	logs.command(name, arg)
+
+	cmd, run := command(networkTimeout, name, arg...)
+	cmd.Dir = dir
+
+	return run(cmd.Run)
+}
+
+func planCommand(note, name string, arg []string) {
+	if plannedCommands == nil {
+		return
+	}
+
+	line := strings.Join(append([]string{name}, arg...), " ")
+	if note != "" {
+		line += "  (" + note + ")"
+	}
+
+	*plannedCommands = append(*plannedCommands, line)
+}
+
+// commandOutput runs name in dir, the working directory when dir is
+// empty, and returns its standard output.
+func commandOutput(dir, name string, arg ...string) ([]byte, error) {
+	var out []byte
+
+	cmd, run := command(commandTimeout, name, arg...)
+	cmd.Dir = dir
+	err := run(func() (err error) {
+		out, err = cmd.Output()
+		return err
+	})
+
+	return out, err
+}
@@ -0,0 +1,58 @@
+package goinit
+
+import "path/filepath"
+
+const (
+	CommunityFlag          = "github-community"
+	CodeownersTemplate     = "templates/community/CODEOWNERS.tmpl"
+	BugReportTemplate      = "templates/community/bug_report.yml"
+	FeatureRequestTemplate = "templates/community/feature_request.yml"
+	IssueConfigTemplate    = "templates/community/issue-config.yml"
+	PullRequestTemplate    = "templates/community/pull_request_template.md"
+	ContributingTemplate   = "templates/community/CONTRIBUTING.md.tmpl"
+	CodeownersFile         = ".github/CODEOWNERS"
+	BugReportFile          = ".github/ISSUE_TEMPLATE/bug_report.yml"
+	FeatureRequestFile     = ".github/ISSUE_TEMPLATE/feature_request.yml"
+	IssueConfigFile        = ".github/ISSUE_TEMPLATE/config.yml"
+	PullRequestFile        = ".github/pull_request_template.md"
+	ContributingFile       = "CONTRIBUTING.md"
+)
+
+// communityFiles are the files of -github-community.
+var communityFiles = []string{CodeownersFile, BugReportFile, FeatureRequestFile, IssueConfigFile, PullRequestFile, ContributingFile}
+
+// contributingData is the data CONTRIBUTING.md is rendered with, which
+// walks through the targets of the task runner when the project has its
+// file, and Runner is the task runner.
+type contributingData struct {
+	projectContext
+	Runner string
+}
+
+// createGithubCommunity writes the CODEOWNERS, owned by the GitHub owner of
+// the module path, the issue forms and pull request template, and a
+// CONTRIBUTING.md.
+func createGithubCommunity(dir string, opts options) error {
+	ctx := newProjectContext(dir, opts)
+
+	err := createFiles(dir, []templateFile{
+		{BugReportFile, BugReportTemplate},
+		{FeatureRequestFile, FeatureRequestTemplate},
+		{IssueConfigFile, IssueConfigTemplate},
+		{PullRequestFile, PullRequestTemplate},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := renderFiles(dir, []templateFile{{CodeownersFile, CodeownersTemplate}}, ctx); err != nil {
+		return err
+	}
+
+	data := contributingData{projectContext: ctx}
+	if exists(filepath.Join(dir, taskRunners[ctx.TaskRunner].file)) {
+		data.Runner = ctx.TaskRunner
+	}
+
+	return renderFiles(dir, []templateFile{{ContributingFile, ContributingTemplate}}, data)
+}
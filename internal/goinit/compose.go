@@ -0,0 +1,102 @@
+package goinit
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ComposeFlag             = "compose"
+	ComposeServicesFlag     = "compose-services"
+	ComposePostgres         = "postgres"
+	ComposeRedis            = "redis"
+	ComposeTemplate         = "templates/compose/docker-compose.yml.tmpl"
+	ComposeMakefileTemplate = "templates/compose/compose.mk"
+	ComposeFile             = "docker-compose.yml"
+)
+
+// composePorts maps the archetypes serving on $ADDR to the port they
+// listen on by default, which the app service publishes.
+var composePorts = map[string]string{
+	TypeAPI:     "8080",
+	TypeOpenAPI: "8080",
+	TypeGRPC:    "50051",
+}
+
+// composeServices returns the comma separated -compose-services, checking
+// each is supported.
+func composeServices(s string) ([]string, error) {
+	var services []string
+
+	for _, service := range strings.Split(s, ",") {
+		service = strings.TrimSpace(service)
+
+		switch service {
+		case "":
+			continue
+		case ComposePostgres, ComposeRedis:
+			services = append(services, service)
+		default:
+			return nil, fmt.Errorf("unsupported compose service %q, use postgres or redis", service)
+		}
+	}
+
+	return services, nil
+}
+
+// validateCompose checks -compose and its services, which the database of
+// -db provides when it has a server.
+func (o options) validateCompose() error {
+	services, err := composeServices(o.composeServices)
+	if err != nil {
+		return err
+	}
+
+	if len(services) > 0 && !o.compose {
+		return errors.New("-compose-services selects the services -compose runs next to the app")
+	}
+
+	if o.compose && o.projectType == TypeLib {
+		return errors.New("-compose runs the project's command, -type lib has none")
+	}
+
+	for _, s := range services {
+		if s == ComposePostgres && o.db != "" && o.db != DBPostgres {
+			return fmt.Errorf("-compose-services postgres is the database of -db postgres, it cannot be used with -db %s", o.db)
+		}
+	}
+
+	return nil
+}
+
+// hasComposeFile reports whether the options generate a docker-compose.yml:
+// -compose, or the server of a -db database.
+func (o options) hasComposeFile() bool {
+	return o.compose || (o.db != "" && o.db != DBSQLite)
+}
+
+// createCompose writes the docker-compose.yml of the local development
+// environment: the database of -db and, with -compose, the app built from
+// the project's Dockerfile, which it makes unless an option did, next to the
+// -compose-services it connects to, and the up and down targets.
+func createCompose(dir string, opts options) error {
+	ctx := newProjectContext(dir, opts)
+
+	if ctx.Compose && !exists(filepath.Join(dir, Dockerfile)) {
+		if err := createDockerfile(dir, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := renderFile(dir, ComposeFile, templatesFS, ComposeTemplate, ctx); err != nil {
+		return fmt.Errorf("error creating %s: %w", ComposeFile, err)
+	}
+
+	if !ctx.Compose {
+		return nil
+	}
+
+	return appendTargets(dir, ctx.TaskRunner, ComposeMakefileTemplate, nil)
+}
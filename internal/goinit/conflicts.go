@@ -0,0 +1,53 @@
+package goinit
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+const (
+	ForceFlag        = "force"
+	SkipExistingFlag = "skip-existing"
+)
+
+// conflictPolicy is what is done with the files that exist in a directory
+// goinit writes into: -here and add fail listing them unless -force
+// overwrites them or -skip-existing keeps them.
+type conflictPolicy int
+
+const (
+	conflictFail conflictPolicy = iota
+	conflictSkip
+	conflictOverwrite
+)
+
+// conflictFlags defines -force and -skip-existing on fs. The returned
+// function reads the policy they select once fs is parsed.
+func conflictFlags(fs *flag.FlagSet) func() (conflictPolicy, error) {
+	force := fs.Bool(ForceFlag, false, "overwrite the files that already exist")
+	skipExisting := fs.Bool(SkipExistingFlag, false, "keep the files that already exist and add the rest")
+
+	return func() (conflictPolicy, error) {
+		switch {
+		case *force && *skipExisting:
+			return conflictFail, fmt.Errorf("-%s and -%s cannot be combined", ForceFlag, SkipExistingFlag)
+		case *force:
+			return conflictOverwrite, nil
+		case *skipExisting:
+			return conflictSkip, nil
+		}
+
+		return conflictFail, nil
+	}
+}
+
+// conflictError lists the files in the way, of which there is at least one.
+func conflictError(conflicts []string) error {
+	exist := "already exist"
+	if len(conflicts) == 1 {
+		exist = "already exists"
+	}
+
+	return fmt.Errorf("%s %s, nothing was added, use -%s to overwrite or -%s to keep them", strings.Join(conflicts, ", "), exist, ForceFlag, SkipExistingFlag)
+}
@@ -0,0 +1,237 @@
+package goinit
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// projectContext is the data the project's Makefile, GoReleaser
+// configuration and release workflow are rendered with.
+type projectContext struct {
+	ProjectName string
+	ModulePath  string
+	Author      string
+	GoVersion   string
+	Year        int
+	// PackageName is the project name as a Go package name.
+	PackageName string
+	// MainPackage is the package path of the project's command.
+	MainPackage string
+	// Library is set for -type lib, which builds no binary to release.
+	Library bool
+	// CI is the -ci provider, which the release configuration targets.
+	CI string
+	// Owner and Repository name the GitHub repository of a github.com
+	// module path, and are empty for other module paths.
+	Owner      string
+	Repository string
+	// Router is the -router of the api archetype.
+	Router string
+	// VersionPackage is the package whose version variables the builds set
+	// with -ldflags, empty when the project has none.
+	VersionPackage string
+	// Workspace is set for the root module of a go.work, whose Makefile
+	// and CI workflow run on every module.
+	Workspace bool
+	// Platforms are the space separated GOOS/GOARCH pairs the Makefile
+	// cross-compiles for.
+	Platforms string
+	// Targets are the GoReleaser build targets of -build-targets, empty when
+	// the build matrix is the default one.
+	Targets []string
+	// TaskRunner is the -task-runner, the command running the targets.
+	TaskRunner string
+	// Worker is set for -type worker, whose packages install its systemd
+	// unit.
+	Worker bool
+	// Nfpm is set when GoReleaser packages the binary as .deb, .rpm and,
+	// but for the worker, .apk.
+	Nfpm bool
+	// BrewTapOwner and BrewTapName name the GitHub repository of the
+	// -brew-tap, and are empty without it.
+	BrewTapOwner string
+	BrewTapName  string
+	// DockerImage is the image GoReleaser publishes for the DockerArchs,
+	// in DockerRegistry, empty without -docker-images.
+	DockerImage    string
+	DockerRegistry string
+	DockerArchs    []string
+	// SupplyChain is set when the release signs the checksums, and the
+	// images, keylessly with cosign and publishes syft SBOMs.
+	SupplyChain bool
+	// Changelog is the -changelog tool the release workflow runs.
+	Changelog string
+	// DB is the -db of the api archetype, with the database/sql driver
+	// connecting to it, the URL of the local database and the -migrations
+	// tool.
+	DB          string
+	DBDriver    string
+	DatabaseURL string
+	Migrations  string
+	// Compose is set when docker-compose.yml runs the app, on ComposePort
+	// and connected to the ComposeDB database at ComposeDatabaseURL and to
+	// Redis when ComposeRedis is set. ComposeDB is also the database of a
+	// -db server without -compose.
+	Compose            bool
+	ComposePort        string
+	ComposeDB          string
+	ComposeDatabaseURL string
+	ComposeRedis       bool
+}
+
+func newProjectContext(dir string, opts options) projectContext {
+	ctx := projectContext{
+		ProjectName: opts.projectName,
+		ModulePath:  opts.modulePath(),
+		Author:      gitMaintainer(dir),
+		GoVersion:   opts.goVersionIn(dir),
+		Year:        buildTime().Year(),
+		PackageName: goPackageName(opts.projectName),
+		MainPackage: ".",
+		CI:          opts.ciProvider(),
+		Router:      opts.router,
+		Library:     opts.projectType == TypeLib,
+		Workspace:   opts.workspace || isWorkspace(dir),
+		TaskRunner:  opts.taskRunner(),
+		Worker:      opts.projectType == TypeWorker,
+		Nfpm:        opts.nfpm || opts.projectType == TypeWorker,
+		SupplyChain: opts.supplyChain,
+		Changelog:   opts.changelog,
+	}
+
+	platforms := strings.Split(DefaultPlatforms, ",")
+	if custom, err := parsePlatforms(opts.buildTargets); err == nil {
+		platforms = custom
+		ctx.Targets = goreleaserTargets(platforms)
+	}
+
+	ctx.Platforms = strings.Join(platforms, " ")
+
+	if ctx.Changelog == "" {
+		ctx.Changelog = ChangelogNone
+	}
+
+	if db, ok := databases[opts.db]; ok {
+		ctx.DB = opts.db
+		ctx.DBDriver = db.driver
+		ctx.DatabaseURL = db.url(ctx.PackageName)
+		ctx.Migrations = opts.migrations
+
+		if ctx.Migrations == "" {
+			ctx.Migrations = MigrationsGoose
+		}
+	}
+
+	if opts.hasComposeFile() {
+		ctx.setCompose(opts)
+	}
+
+	if ctx.Router == "" {
+		ctx.Router = RouterStdlib
+	}
+
+	if opts.projectType == TypeCLI {
+		ctx.VersionPackage = ctx.ModulePath + "/" + path.Dir(CLIRootFile)
+	}
+
+	switch {
+	case opts.mainPackage != "":
+		ctx.MainPackage = opts.mainPackage
+	case opts.projectType != TypeLib && (opts.projectType != "" || !opts.hasMain()):
+		ctx.MainPackage = "./" + path.Join(CmdDir, opts.projectName)
+	}
+
+	if repo, err := githubRepo(ctx.ModulePath); err == nil {
+		ctx.Owner, ctx.Repository, _ = strings.Cut(repo, "/")
+	}
+
+	ctx.BrewTapOwner, ctx.BrewTapName, _ = strings.Cut(opts.brewTap, "/")
+
+	if opts.dockerImages {
+		ctx.DockerImage = dockerImage(ctx)
+		ctx.DockerRegistry, _, _ = strings.Cut(ctx.DockerImage, "/")
+		ctx.DockerArchs = linuxArchs(platforms)
+	}
+
+	return ctx
+}
+
+// goVersionIn returns the -go-version, or else the version of the go
+// directive of the go.mod in dir, or of the installed toolchain when there
+// is none, or of the one goinit was built with when Go is not installed.
+func (o options) goVersionIn(dir string) string {
+	if o.goVersion != "" {
+		return o.goVersion
+	}
+
+	if version := goModDirective(filepath.Join(dir, "go.mod"), "go"); version != "" {
+		return version
+	}
+
+	out, err := commandOutput(dir, "go", "env", "GOVERSION")
+	if err != nil {
+		out = []byte(runtime.Version())
+	}
+
+	// Development toolchains append the commit, as in "go1.23 X:...".
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.TrimPrefix(fields[0], "go")
+}
+
+// goModDirective returns the value of the first directive named key in the
+// go.mod file name, or "" when there is none.
+func goModDirective(name, key string) string {
+	f, err := os.Open(name)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+
+	return ""
+}
+
+// setCompose sets the services of the docker-compose.yml, where the app
+// reaches the database at the host of its service.
+func (ctx *projectContext) setCompose(opts options) {
+	services, _ := composeServices(opts.composeServices)
+
+	ctx.Compose = opts.compose
+	ctx.ComposePort = composePorts[opts.projectType]
+
+	if ctx.DB != "" && ctx.DB != DBSQLite {
+		ctx.ComposeDB = ctx.DB
+	}
+
+	for _, s := range services {
+		switch s {
+		case ComposePostgres:
+			ctx.ComposeDB = DBPostgres
+		case ComposeRedis:
+			ctx.ComposeRedis = true
+		}
+	}
+
+	switch {
+	case !ctx.Compose:
+	case ctx.ComposeDB != "":
+		ctx.ComposeDatabaseURL = strings.Replace(databases[ctx.ComposeDB].url(ctx.PackageName), "localhost", "db", 1)
+	case ctx.DB == DBSQLite:
+		ctx.ComposeDatabaseURL = "/home/nonroot/" + databases[DBSQLite].url(ctx.PackageName)
+	}
+}
@@ -0,0 +1,44 @@
+package goinit
+
+const (
+	CronJobMainTemplate       = "templates/cronjob/main.go.tmpl"
+	CronJobRunnerTemplate     = "templates/cronjob/runner.go.tmpl"
+	CronJobRunnerTestTemplate = "templates/cronjob/runner_internal_test.go.tmpl"
+	CronJobCleanupTemplate    = "templates/cronjob/cleanup.go.tmpl"
+	CronJobManifestTemplate   = "templates/cronjob/cronjob.yaml.tmpl"
+	CronJobRunnerFile         = "internal/jobs/runner.go"
+	CronJobRunnerTestFile     = "internal/jobs/runner_internal_test.go"
+	CronJobCleanupFile        = "internal/jobs/cleanup.go"
+	CronJobManifestFile       = "deploy/k8s/cronjob.yaml"
+)
+
+// createCronJobLayout generates a service running jobs on cron schedules
+// with timeouts and retries, and with -k8s a CronJob running a single job
+// on the cluster's schedule instead.
+func createCronJobLayout(dir string, opts options) error {
+	files := []templateFile{
+		{MainFile, CronJobMainTemplate},
+		{CronJobRunnerFile, CronJobRunnerTemplate},
+		{CronJobRunnerTestFile, CronJobRunnerTestTemplate},
+		{CronJobCleanupFile, CronJobCleanupTemplate},
+	}
+
+	if opts.k8s {
+		files = append(files, templateFile{CronJobManifestFile, CronJobManifestTemplate})
+	}
+
+	if err := renderFiles(dir, files, newPackageInfo(dir, opts)); err != nil {
+		return err
+	}
+
+	if err := createFiles(dir, []templateFile{{ConfigFile, ConfigTemplate}}); err != nil {
+		return err
+	}
+
+	// The CronJob needs an image, -buildx or -docker already created the Dockerfile.
+	if opts.k8s && !opts.hasDockerfile() {
+		return createDockerfile(dir, opts)
+	}
+
+	return nil
+}
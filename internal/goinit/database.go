@@ -0,0 +1,98 @@
+package goinit
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	DBFlag                  = "db"
+	MigrationsFlag          = "migrations"
+	DBPostgres              = "postgres"
+	DBSQLite                = "sqlite"
+	DBMySQL                 = "mysql"
+	MigrationsGoose         = "goose"
+	MigrationsGolangMigrate = "golang-migrate"
+	DBTemplate              = "templates/db/db.go.tmpl"
+	DBHealthTemplate        = "templates/db/health.go.tmpl"
+	MigrationUpTemplate     = "templates/db/create_greetings.up.sql.tmpl"
+	MigrationDownTemplate   = "templates/db/create_greetings.down.sql.tmpl"
+	DBMakefileTemplate      = "templates/db/db.mk.tmpl"
+	DBGitignoreTemplate     = "templates/db/db.gitignore.tmpl"
+	DBFile                  = "internal/db/db.go"
+	DBHealthFile            = "internal/api/health.go"
+	MigrationsDir           = "migrations"
+)
+
+// databases maps the -db values to the database/sql driver the api
+// archetype connects with, and the connection URL of the local database.
+// The sqlite driver is pure Go, so the builds keep CGO_ENABLED=0.
+var databases = map[string]struct {
+	driver string
+	url    func(name string) string
+}{
+	DBPostgres: {"pgx", func(name string) string {
+		return "postgres://" + name + ":" + name + "@localhost:5432/" + name + "?sslmode=disable"
+	}},
+	DBMySQL: {"mysql", func(name string) string {
+		return name + ":" + name + "@tcp(localhost:3306)/" + name + "?parseTime=true"
+	}},
+	DBSQLite: {"sqlite", func(name string) string { return name + ".db" }},
+}
+
+// createDatabase connects the api archetype to the -db database from
+// $DATABASE_URL, with the migrations of the -migrations tool creating an
+// example table and its Make targets. The docker-compose.yml runs the
+// database locally, SQLite has no server, so its file is ignored instead.
+func createDatabase(dir string, opts options) error {
+	ctx := newProjectContext(dir, opts)
+
+	err := renderFiles(dir, []templateFile{
+		{DBFile, DBTemplate},
+		{DBHealthFile, DBHealthTemplate},
+	}, ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := createMigrations(dir, ctx); err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, ctx.TaskRunner, DBMakefileTemplate, ctx); err != nil {
+		return err
+	}
+
+	if ctx.DB == DBSQLite {
+		if err := appendRenderedFile(dir, GitignoreFile, templatesFS, DBGitignoreTemplate, ctx); err != nil {
+			return fmt.Errorf("error updating %s: %w", GitignoreFile, err)
+		}
+	}
+
+	return nil
+}
+
+// createMigrations writes the migration creating the example table in the
+// layout of the migration tool: goose keeps both directions in one file,
+// golang-migrate has an up and a down file.
+func createMigrations(dir string, ctx projectContext) error {
+	if err := ensureDir(filepath.Join(dir, MigrationsDir)); err != nil {
+		return err
+	}
+
+	if ctx.Migrations == MigrationsGoose {
+		name := filepath.Join(MigrationsDir, "00001_create_greetings.sql")
+		for _, t := range []string{MigrationUpTemplate, MigrationDownTemplate} {
+			if err := appendRenderedFile(dir, name, templatesFS, t, ctx); err != nil {
+				return fmt.Errorf("error creating %s: %w", name, err)
+			}
+		}
+
+		return nil
+	}
+
+	return renderFiles(dir, []templateFile{
+		{filepath.Join(MigrationsDir, "000001_create_greetings.up.sql"), MigrationUpTemplate},
+		{filepath.Join(MigrationsDir, "000001_create_greetings.down.sql"), MigrationDownTemplate},
+	}, ctx)
+}
@@ -0,0 +1,173 @@
+package goinit
+
+import (
+	"crypto/rand"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DesktopFyneMainTemplate     = "templates/desktop/fyne_main.go.tmpl"
+	DesktopFyneUITemplate       = "templates/desktop/fyne_ui.go.tmpl"
+	DesktopFyneUITestTemplate   = "templates/desktop/fyne_ui_internal_test.go.tmpl"
+	DesktopFyneAppTemplate      = "templates/desktop/FyneApp.toml.tmpl"
+	DesktopWailsMainTemplate    = "templates/desktop/wails_main.go.tmpl"
+	DesktopWailsAppTemplate     = "templates/desktop/wails_app.go.tmpl"
+	DesktopWailsAppTestTemplate = "templates/desktop/wails_app_internal_test.go.tmpl"
+	DesktopWailsConfigTemplate  = "templates/desktop/wails.json.tmpl"
+	DesktopIndexTemplate        = "templates/desktop/index.html.tmpl"
+	DesktopScriptTemplate       = "templates/desktop/main.js"
+	DesktopWixTemplate          = "templates/desktop/app.wxs.tmpl"
+	DesktopEntryTemplate        = "templates/desktop/app.desktop.tmpl"
+	DesktopMakefileTemplate     = "templates/desktop/desktop.mk.tmpl"
+	DesktopWorkflowTemplate     = "templates/desktop/desktop.yml"
+	DesktopGitignoreTemplate    = "templates/desktop/desktop.gitignore"
+	DesktopFyneIconFile         = "Icon.png"
+	DesktopWailsIconFile        = "build/appicon.png"
+	DesktopWixFile              = "packaging/windows/app.wxs"
+	FrameworkFyne               = "fyne"
+	FrameworkWails              = "wails"
+	DesktopIconSize             = 512
+)
+
+// desktopData is what the desktop templates are rendered with.
+type desktopData struct {
+	packageInfo
+	Framework string
+	// Manufacturer is the maintainer without the email address, which
+	// cannot appear in the WiX XML attribute.
+	Manufacturer string
+	// AppID is the reverse DNS identifier of the application.
+	AppID string
+	// UpgradeCode identifies the application to Windows Installer across
+	// versions, so it must not change after the first release.
+	UpgradeCode string
+	Icon        string
+}
+
+// createDesktopLayout generates a Fyne or Wails application with its
+// assets embedded, Make targets packaging it as a dmg, msi or AppImage and
+// a release workflow building those on each platform. The application
+// uses cgo, so the workflow replaces the GoReleaser release.
+func createDesktopLayout(dir string, opts options) error {
+	info := newPackageInfo(dir, opts)
+
+	upgradeCode, err := newUUID()
+	if err != nil {
+		return err
+	}
+
+	data := desktopData{
+		packageInfo:  info,
+		Framework:    opts.framework,
+		Manufacturer: strings.TrimSpace(strings.Split(info.Maintainer, "<")[0]),
+		AppID:        "com.example." + strings.ReplaceAll(info.Package, "-", ""),
+		UpgradeCode:  upgradeCode,
+		Icon:         DesktopFyneIconFile,
+	}
+
+	files := []templateFile{
+		{DesktopWixFile, DesktopWixTemplate},
+		{"packaging/linux/" + info.Package + ".desktop", DesktopEntryTemplate},
+	}
+
+	if opts.framework == FrameworkFyne {
+		files = append(files,
+			templateFile{MainFile, DesktopFyneMainTemplate},
+			templateFile{"internal/ui/ui.go", DesktopFyneUITemplate},
+			templateFile{"internal/ui/ui_internal_test.go", DesktopFyneUITestTemplate},
+			templateFile{"FyneApp.toml", DesktopFyneAppTemplate},
+		)
+	} else {
+		data.Icon = DesktopWailsIconFile
+		files = append(files,
+			templateFile{MainFile, DesktopWailsMainTemplate},
+			templateFile{"app.go", DesktopWailsAppTemplate},
+			templateFile{"app_internal_test.go", DesktopWailsAppTestTemplate},
+			templateFile{"wails.json", DesktopWailsConfigTemplate},
+			templateFile{"frontend/dist/index.html", DesktopIndexTemplate},
+		)
+	}
+
+	if err := renderFiles(dir, files, data); err != nil {
+		return err
+	}
+
+	if opts.framework == FrameworkWails {
+		if err := createFiles(dir, []templateFile{{"frontend/dist/main.js", DesktopScriptTemplate}}); err != nil {
+			return err
+		}
+	}
+
+	if err := writeIcon(filepath.Join(dir, data.Icon)); err != nil {
+		return fmt.Errorf("error creating %s: %w", data.Icon, err)
+	}
+
+	if err := createFile(dir, ReleaserFile, templatesFS, DesktopWorkflowTemplate); err != nil {
+		return fmt.Errorf("error creating %s: %w", ReleaserFile, err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, GoreleaserFile)); err != nil {
+		return fmt.Errorf("error removing %s: %w", GoreleaserFile, err)
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, DesktopMakefileTemplate, data); err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GitignoreFile, templatesFS, DesktopGitignoreTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GitignoreFile, err)
+	}
+
+	return nil
+}
+
+// writeIcon writes a placeholder application icon to replace with the
+// real one.
+func writeIcon(name string) error {
+	img := image.NewRGBA(image.Rect(0, 0, DesktopIconSize, DesktopIconSize))
+	background := color.RGBA{0x00, 0x7d, 0x9c, 0xff}
+	foreground := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	margin := DesktopIconSize / 4
+
+	for y := 0; y < DesktopIconSize; y++ {
+		for x := 0; x < DesktopIconSize; x++ {
+			c := background
+			if x >= margin && x < DesktopIconSize-margin && y >= margin && y < DesktopIconSize-margin {
+				c = foreground
+			}
+
+			img.Set(x, y, c)
+		}
+	}
+
+	if err := ensureDir(filepath.Dir(name)); err != nil {
+		return err
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
+
+// newUUID returns a random version 4 UUID in the upper case form WiX uses.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating UUID: %w", err)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])), nil
+}
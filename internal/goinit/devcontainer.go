@@ -0,0 +1,18 @@
+package goinit
+
+const (
+	DevcontainerTemplate           = "templates/devcontainer/devcontainer.json.tmpl"
+	DevcontainerDockerfileTemplate = "templates/devcontainer/Dockerfile.tmpl"
+	DevcontainerFile               = ".devcontainer/devcontainer.json"
+	DevcontainerDockerfile         = ".devcontainer/Dockerfile"
+)
+
+// createDevcontainer writes a dev container for Codespaces and VS Code
+// Remote, built on the Go image of the project's Go version with the
+// linters of the pre-commit hook installed.
+func createDevcontainer(dir string, opts options) error {
+	return renderFiles(dir, []templateFile{
+		{DevcontainerFile, DevcontainerTemplate},
+		{DevcontainerDockerfile, DevcontainerDockerfileTemplate},
+	}, newProjectContext(dir, opts))
+}
@@ -0,0 +1,384 @@
+package goinit
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultDiffContext = 3
+	// MaxDiffCells bounds the memory of the line diff. Larger changes are
+	// shown as the old lines removed and the new ones added.
+	MaxDiffCells = 4 << 20
+)
+
+// projectDiff generates the project again from its manifest into a
+// temporary directory and prints how the working copy differs from it.
+func projectDiff(args []string) error {
+	set := flag.NewFlagSet("diff", flag.ContinueOnError)
+	context := set.Int("U", DefaultDiffContext, "lines of context around each change")
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	manifest, err := readManifest(ManifestFile)
+	if err != nil {
+		return err
+	}
+
+	opts, err := manifest.options()
+	if err != nil {
+		return err
+	}
+
+	// Generating for comparison must not change the GitHub repository, nor
+	// commit.
+	opts.labels, opts.protect, opts.commit, opts.createRemote = false, false, false, false
+
+	restore, err := useTemplatesAt(manifest.Template, manifest.TemplateRevision)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current working directory: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp("", "goinit-diff-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := generateIn(rootCtx, tmp, opts); err != nil {
+		return err
+	}
+
+	changed, err := diffTrees(os.Stdout, filepath.Join(tmp, opts.projectName), wd, *context)
+	if err != nil {
+		return err
+	}
+
+	if changed == 0 {
+		fmt.Println("No differences from the template")
+	}
+
+	return nil
+}
+
+// diffTrees writes a unified diff of the files of project against the
+// ones of template and returns the number of files differing.
+func diffTrees(w io.Writer, template, project string, context int) (int, error) {
+	templateFiles, err := walkFiles(template)
+	if err != nil {
+		return 0, err
+	}
+
+	projectFiles, err := trackedFiles(project)
+	if err != nil {
+		return 0, err
+	}
+
+	names := make(map[string]bool)
+	for name := range templateFiles {
+		names[name] = true
+	}
+
+	for name := range projectFiles {
+		names[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+
+	sort.Strings(sorted)
+
+	changed := 0
+
+	for _, name := range sorted {
+		switch {
+		case !projectFiles[name]:
+			fmt.Fprintf(w, "Only in template: %s\n", name)
+		case !templateFiles[name]:
+			fmt.Fprintf(w, "Only in project: %s\n", name)
+		default:
+			old, err := os.ReadFile(filepath.Join(template, name))
+			if err != nil {
+				return changed, err
+			}
+
+			current, err := os.ReadFile(filepath.Join(project, name))
+			if err != nil {
+				return changed, err
+			}
+
+			if bytes.Equal(old, current) {
+				continue
+			}
+
+			if bytes.IndexByte(old, 0) >= 0 || bytes.IndexByte(current, 0) >= 0 {
+				fmt.Fprintf(w, "Binary files template/%s and project/%s differ\n", name, name)
+			} else {
+				writeUnifiedDiff(w, "template/"+name, "project/"+name, splitLines(string(old)), splitLines(string(current)), context)
+			}
+		}
+
+		changed++
+	}
+
+	return changed, nil
+}
+
+// walkFiles returns the regular files under root, relative to it, leaving
+// out the repository and the manifest.
+func walkFiles(root string) (map[string]bool, error) {
+	files := make(map[string]bool)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if rel = filepath.ToSlash(rel); rel != ManifestFile {
+			files[rel] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing files of %s: %w", root, err)
+	}
+
+	return files, nil
+}
+
+// trackedFiles returns the files of the repository at root that git does
+// not ignore, so build output is left out, or all files outside git.
+func trackedFiles(root string) (map[string]bool, error) {
+	var out []byte
+
+	cmd, run := command(commandTimeout, "git", "ls-files", "--cached", "--others", "--exclude-standard", "-z")
+	cmd.Dir = root
+
+	err := run(func() (err error) {
+		out, err = cmd.Output()
+		return err
+	})
+	if err != nil {
+		return walkFiles(root)
+	}
+
+	files := make(map[string]bool)
+
+	for _, name := range strings.Split(string(out), "\x00") {
+		info, err := os.Lstat(filepath.Join(root, name))
+		if name == "" || name == ManifestFile || err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		files[name] = true
+	}
+
+	return files, nil
+}
+
+// splitLines splits s into lines, keeping their line endings.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// diffLines returns the edit script turning a into b, from the longest
+// common subsequence of their lines.
+func diffLines(a, b []string) []diffOp {
+	var prefix, suffix []diffOp
+
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		prefix = append(prefix, diffOp{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append([]diffOp{{' ', a[len(a)-1]}}, suffix...)
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	ops := prefix
+
+	if len(a)*len(b) > MaxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+
+		return append(ops, suffix...)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	return append(ops, suffix...)
+}
+
+// writeUnifiedDiff writes the changes from a to b, named aName and bName,
+// in the unified format, with context unchanged lines around each change.
+func writeUnifiedDiff(w io.Writer, aName, bName string, a, b []string, context int) {
+	ops := diffLines(a, b)
+
+	// aLine[k] and bLine[k] are the lines of a and b before ops[k].
+	aLine := make([]int, len(ops)+1)
+	bLine := make([]int, len(ops)+1)
+
+	for k, op := range ops {
+		aLine[k+1], bLine[k+1] = aLine[k], bLine[k]
+
+		if op.kind != '+' {
+			aLine[k+1]++
+		}
+
+		if op.kind != '-' {
+			bLine[k+1]++
+		}
+	}
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", aName, bName)
+
+	for k := 0; k < len(ops); {
+		for k < len(ops) && ops[k].kind == ' ' {
+			k++
+		}
+
+		if k == len(ops) {
+			break
+		}
+
+		start := k - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk over changes separated by at most twice the
+		// context, which would otherwise overlap.
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+
+			end = next
+		}
+
+		stop := end + context
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		fmt.Fprintf(w, "@@ -%s +%s @@\n",
+			hunkRange(aLine[start], aLine[stop]-aLine[start]),
+			hunkRange(bLine[start], bLine[stop]-bLine[start]))
+
+		for _, op := range ops[start:stop] {
+			fmt.Fprintf(w, "%c%s", op.kind, op.line)
+
+			if !strings.HasSuffix(op.line, "\n") {
+				fmt.Fprint(w, "\n\\ No newline at end of file\n")
+			}
+		}
+
+		k = stop
+	}
+}
+
+// hunkRange formats the start and length of a hunk's lines, which start
+// at 1 unless the hunk is empty on that side.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
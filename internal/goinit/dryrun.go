@@ -0,0 +1,122 @@
+package goinit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const DryRunFlag = "dry-run"
+
+// dryRun prints the directories and files generating opts would create and
+// the commands it would run. The project is generated in a temporary
+// directory, so the working directory is left untouched.
+func dryRun(w io.Writer, opts options) error {
+	if exists(opts.projectName) {
+		return fmt.Errorf("folder %s already exists", opts.projectName)
+	}
+
+	var tree bytes.Buffer
+
+	commands, err := planProject(&tree, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.createRemote {
+		commands = append(commands, "create the repository "+opts.modulePath()+" through the GitHub API and add it as "+GitRemote)
+	}
+
+	if opts.createRemote && opts.push {
+		commands = append(commands, "git push -u "+GitRemote+" "+opts.branch)
+	}
+
+	if opts.labels {
+		commands = append(commands, "create the labels and milestone in "+opts.modulePath()+" through the GitHub API")
+	}
+
+	if opts.protect {
+		commands = append(commands, "protect the default branch of "+opts.modulePath()+" through the GitHub API")
+	}
+
+	fmt.Fprintf(w, "Would create %s (module %s):\n\n", opts.projectName+"/", opts.modulePath())
+
+	if _, err := tree.WriteTo(w); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nWould run, in %s:\n\n", opts.projectName+"/")
+
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s\n", c)
+	}
+
+	return nil
+}
+
+// planProject generates opts in a temporary directory, lists its
+// directories and files on w and returns the commands generation ran,
+// leaving out the ones that download. The GitHub API calls are not made.
+func planProject(w io.Writer, opts options) ([]string, error) {
+	tmp, err := os.MkdirTemp("", "goinit-dry-run-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	commands := []string{}
+	plannedCommands = &commands
+
+	defer func() { plannedCommands = nil }()
+
+	opts.labels, opts.protect, opts.createRemote = false, false, false
+
+	if err := generateIn(rootCtx, tmp, opts); err != nil {
+		return nil, err
+	}
+
+	if err := writeTree(w, filepath.Join(tmp, opts.projectName)); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+// writeTree lists the directories and files under root, indented by depth.
+// The contents of .git are left out, git init creates them.
+func writeTree(w io.Writer, root string) error {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		indent := strings.Repeat("  ", strings.Count(filepath.ToSlash(rel), "/")+1)
+
+		if !d.IsDir() {
+			fmt.Fprintf(w, "%s%s\n", indent, d.Name())
+			return nil
+		}
+
+		fmt.Fprintf(w, "%s%s/\n", indent, d.Name())
+
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error listing the generated files: %w", err)
+	}
+
+	return nil
+}
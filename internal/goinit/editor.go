@@ -0,0 +1,29 @@
+package goinit
+
+const (
+	EditorFlag               = "editor"
+	EditorVSCode             = "vscode"
+	EditorNone               = "none"
+	EditorconfigTemplate     = "templates/.editorconfig"
+	EditorconfigFile         = ".editorconfig"
+	VSCodeSettingsTemplate   = "templates/vscode/settings.json"
+	VSCodeExtensionsTemplate = "templates/vscode/extensions.json"
+	VSCodeSettingsFile       = ".vscode/settings.json"
+	VSCodeExtensionsFile     = ".vscode/extensions.json"
+)
+
+// editors maps the -editor values to the function generating the editor's
+// project settings. Every project gets the .editorconfig all editors read.
+var editors = map[string]func(dir string, opts options) error{
+	EditorVSCode: createVSCodeSettings,
+	EditorNone:   func(string, options) error { return nil },
+}
+
+// createVSCodeSettings writes the workspace settings running gopls and
+// golangci-lint on save, and the recommended extensions.
+func createVSCodeSettings(dir string, _ options) error {
+	return createFiles(dir, []templateFile{
+		{VSCodeSettingsFile, VSCodeSettingsTemplate},
+		{VSCodeExtensionsFile, VSCodeExtensionsTemplate},
+	})
+}
@@ -0,0 +1,299 @@
+package goinit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	GithubAPIURL     = "https://api.github.com"
+	GithubHost       = "github.com/"
+	InitialMilestone = "v0.1.0"
+	GitRemote        = "origin"
+)
+
+type githubLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// defaultLabels covers issue triage and the semver labels read by the
+// release tooling (release-drafter's version resolver).
+var defaultLabels = []githubLabel{
+	{"bug", "d73a4a", "Something isn't working"},
+	{"enhancement", "a2eeef", "New feature or request"},
+	{"documentation", "0075ca", "Improvements or additions to documentation"},
+	{"good first issue", "7057ff", "Good for newcomers"},
+	{"help wanted", "008672", "Extra attention is needed"},
+	{"dependencies", "0366d6", "Updates a dependency"},
+	{"semver:major", "b60205", "Breaking change, bumps the major version"},
+	{"semver:minor", "fbca04", "New functionality, bumps the minor version"},
+	{"semver:patch", "0e8a16", "Bug fix, bumps the patch version"},
+	{"skip-changelog", "cfd3d7", "Leave out of the release notes"},
+}
+
+type githubClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+type githubError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *githubError) Error() string {
+	return fmt.Sprintf("github api: %d %s", e.StatusCode, e.Message)
+}
+
+// newGithubClient authenticates with GITHUB_TOKEN or GH_TOKEN, falling back
+// to the token of a logged in gh CLI.
+func newGithubClient() (*githubClient, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+
+	if token == "" {
+		out, err := commandOutput("", "gh", "auth", "token")
+		if err != nil {
+			return nil, errors.New("no GitHub token found, set GITHUB_TOKEN or log in with `gh auth login`")
+		}
+
+		token = strings.TrimSpace(string(out))
+	}
+
+	return &githubClient{
+		baseURL: GithubAPIURL,
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (c *githubClient) do(method, path string, body, out any) error {
+	var reader io.Reader
+
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request: %w", err)
+		}
+
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(rootCtx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &githubError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(apiErr)
+
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isUnprocessable reports whether err is GitHub's response to creating
+// something that already exists.
+func isUnprocessable(err error) bool {
+	var apiErr *githubError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity
+}
+
+// githubRepo returns the owner/name of a github.com module path.
+func githubRepo(modulePath string) (string, error) {
+	repo := strings.TrimPrefix(modulePath, GithubHost)
+	if repo == modulePath || strings.Count(repo, "/") != 1 {
+		return "", fmt.Errorf("module path %q is not a github.com/<owner>/<repo> path", modulePath)
+	}
+
+	return repo, nil
+}
+
+// bootstrapLabels creates the default labels, updating the ones GitHub
+// already created with the repository, and the initial milestone.
+func bootstrapLabels(modulePath string) error {
+	repo, err := githubRepo(modulePath)
+	if err != nil {
+		return err
+	}
+
+	client, err := newGithubClient()
+	if err != nil {
+		return err
+	}
+
+	for _, label := range defaultLabels {
+		err := client.do(http.MethodPost, "/repos/"+repo+"/labels", label, nil)
+		if isUnprocessable(err) {
+			err = client.do(http.MethodPatch, "/repos/"+repo+"/labels/"+url.PathEscape(label.Name), label, nil)
+		}
+
+		if err != nil {
+			return fmt.Errorf("error creating label %q: %w", label.Name, err)
+		}
+	}
+
+	milestone := map[string]string{"title": InitialMilestone, "description": "First release"}
+	if err := client.do(http.MethodPost, "/repos/"+repo+"/milestones", milestone, nil); err != nil && !isUnprocessable(err) {
+		return fmt.Errorf("error creating milestone %s: %w", InitialMilestone, err)
+	}
+
+	return nil
+}
+
+type newRepository struct {
+	Name    string `json:"name"`
+	Private bool   `json:"private"`
+}
+
+// createRemote creates the GitHub repository of the module path, under the
+// authenticated user or else the organization owning it, and adds it as
+// the origin of the repository in dir. A repository that already exists
+// is only added. Its URL follows the git protocol gh is configured with.
+func createRemote(dir, modulePath string, private bool) error {
+	repo, err := githubRepo(modulePath)
+	if err != nil {
+		return err
+	}
+
+	client, err := newGithubClient()
+	if err != nil {
+		return err
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+
+	if err := client.do(http.MethodGet, "/user", nil, &user); err != nil {
+		return fmt.Errorf("error reading the authenticated user: %w", err)
+	}
+
+	owner, name, _ := strings.Cut(repo, "/")
+
+	path := "/orgs/" + url.PathEscape(owner) + "/repos"
+	if strings.EqualFold(owner, user.Login) {
+		path = "/user/repos"
+	}
+
+	err = client.do(http.MethodPost, path, newRepository{Name: name, Private: private}, nil)
+	if err != nil && !isUnprocessable(err) {
+		return fmt.Errorf("error creating repository %s: %w", repo, err)
+	}
+
+	remoteURL := "https://" + GithubHost + repo + ".git"
+	if commandLine("gh", "config", "get", "git_protocol") == "ssh" {
+		remoteURL = SSHGitUser + "@" + GithubDomain + ":" + repo + ".git"
+	}
+
+	if err := runCommand(dir, "git", "remote", "add", GitRemote, remoteURL); err != nil {
+		return fmt.Errorf("error adding %s as %s: %w", remoteURL, GitRemote, err)
+	}
+
+	return nil
+}
+
+type statusChecks struct {
+	Strict   bool     `json:"strict"`
+	Contexts []string `json:"contexts"`
+}
+
+type pullRequestReviews struct {
+	DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+	RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+}
+
+type branchProtection struct {
+	RequiredStatusChecks       *statusChecks       `json:"required_status_checks"`
+	EnforceAdmins              bool                `json:"enforce_admins"`
+	RequiredPullRequestReviews *pullRequestReviews `json:"required_pull_request_reviews"`
+	Restrictions               *struct{}           `json:"restrictions"`
+	RequiredLinearHistory      bool                `json:"required_linear_history"`
+	AllowForcePushes           bool                `json:"allow_force_pushes"`
+	AllowDeletions             bool                `json:"allow_deletions"`
+}
+
+// requiredChecks returns the names of the generated workflow jobs that run
+// on pull requests and therefore can be required to pass before merging.
+func requiredChecks(dir string, opts options) []string {
+	if opts.ciProvider() != CIGithub {
+		return nil
+	}
+
+	// The test job runs once per Go version of its matrix.
+	return []string{"test (go " + opts.goVersionIn(dir) + ")", "test (go stable)", "lint", "cibuild"}
+}
+
+// protectDefaultBranch requires reviews, linear history and the given
+// status checks on the repository's default branch. The branch has to exist,
+// so the initial commit must have been pushed.
+func protectDefaultBranch(modulePath string, checks []string) error {
+	repo, err := githubRepo(modulePath)
+	if err != nil {
+		return err
+	}
+
+	client, err := newGithubClient()
+	if err != nil {
+		return err
+	}
+
+	var info struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+
+	if err := client.do(http.MethodGet, "/repos/"+repo, nil, &info); err != nil {
+		return fmt.Errorf("error reading repository %s: %w", repo, err)
+	}
+
+	protection := branchProtection{
+		RequiredPullRequestReviews: &pullRequestReviews{
+			DismissStaleReviews:          true,
+			RequiredApprovingReviewCount: 1,
+		},
+		RequiredLinearHistory: true,
+	}
+
+	if len(checks) > 0 {
+		protection.RequiredStatusChecks = &statusChecks{Strict: true, Contexts: checks}
+	}
+
+	path := "/repos/" + repo + "/branches/" + url.PathEscape(info.DefaultBranch) + "/protection"
+	if err := client.do(http.MethodPut, path, protection, nil); err != nil {
+		return fmt.Errorf("error protecting branch %s: %w", info.DefaultBranch, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package goinit
+
+const (
+	GithubAppMainTemplate        = "templates/githubapp/main.go.tmpl"
+	GithubAppWebhookTemplate     = "templates/githubapp/webhook.go.tmpl"
+	GithubAppWebhookTestTemplate = "templates/githubapp/webhook_internal_test.go.tmpl"
+	GithubAppAuthTemplate        = "templates/githubapp/auth.go.tmpl"
+	GithubAppAuthTestTemplate    = "templates/githubapp/auth_internal_test.go.tmpl"
+	GithubAppIssuesTemplate      = "templates/githubapp/issues.go.tmpl"
+	GithubAppIssuesTestTemplate  = "templates/githubapp/issues_internal_test.go.tmpl"
+	GithubAppManifestTemplate    = "templates/githubapp/app-manifest.json.tmpl"
+	GithubAppDocTemplate         = "templates/githubapp/github-app.md"
+	GithubAppDir                 = "internal/githubapp/"
+	GithubAppHandlersDir         = "internal/handlers/"
+	GithubAppManifestFile        = "app-manifest.json"
+	GithubAppDocFile             = "docs/github-app.md"
+)
+
+// createGithubAppLayout generates a GitHub App server: webhook signature
+// verification, app and installation authentication, an example issues
+// handler and the manifest to register the app with.
+func createGithubAppLayout(dir string, opts options) error {
+	err := renderFiles(dir, []templateFile{
+		{MainFile, GithubAppMainTemplate},
+		{GithubAppDir + "webhook.go", GithubAppWebhookTemplate},
+		{GithubAppDir + "webhook_internal_test.go", GithubAppWebhookTestTemplate},
+		{GithubAppDir + "auth.go", GithubAppAuthTemplate},
+		{GithubAppDir + "auth_internal_test.go", GithubAppAuthTestTemplate},
+		{GithubAppHandlersDir + "issues.go", GithubAppIssuesTemplate},
+		{GithubAppHandlersDir + "issues_internal_test.go", GithubAppIssuesTestTemplate},
+		{GithubAppManifestFile, GithubAppManifestTemplate},
+	}, newPackageInfo(dir, opts))
+	if err != nil {
+		return err
+	}
+
+	return createFiles(dir, []templateFile{{GithubAppDocFile, GithubAppDocTemplate}})
+}
@@ -0,0 +1,62 @@
+package goinit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	BrewTapFlag                  = "brew-tap"
+	DockerImagesFlag             = "docker-images"
+	NfpmFlag                     = "nfpm"
+	SupplyChainFlag              = "supply-chain"
+	GoreleaserDockerfileTemplate = "templates/release/goreleaser.Dockerfile.tmpl"
+	GoreleaserDockerfile         = "goreleaser.Dockerfile"
+	// PlaceholderRegistry is the registry of the images of projects whose
+	// module path is not on GitHub, to be replaced with theirs.
+	PlaceholderRegistry = "registry.example.com"
+	BrewTapSecret       = "HOMEBREW_TAP_GITHUB_TOKEN"
+)
+
+var brewTapPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*/[A-Za-z0-9._-]+$`)
+
+// checkBrewTap checks that tap is the owner/repo of a GitHub repository.
+func checkBrewTap(tap string) error {
+	if !brewTapPattern.MatchString(tap) {
+		return fmt.Errorf("invalid Homebrew tap %q, give its GitHub repository as owner/repo, such as acme/homebrew-tap", tap)
+	}
+
+	return nil
+}
+
+// dockerImage returns the image GoReleaser publishes for -docker-images:
+// the project's GitHub Container Registry image, or one in a placeholder
+// registry for other module paths.
+func dockerImage(ctx projectContext) string {
+	if ctx.Owner == "" {
+		return PlaceholderRegistry + "/" + strings.ToLower(ctx.ProjectName)
+	}
+
+	return "ghcr.io/" + strings.ToLower(ctx.Owner+"/"+ctx.Repository)
+}
+
+// linuxArchs returns the architectures of the linux platforms, which the
+// images are built for.
+func linuxArchs(platforms []string) []string {
+	var archs []string
+
+	for _, p := range platforms {
+		if goos, arch, _ := strings.Cut(p, "/"); goos == "linux" {
+			archs = append(archs, arch)
+		}
+	}
+
+	return archs
+}
+
+// createGoreleaserDockerfile writes the Dockerfile of the images GoReleaser
+// builds around the released binary.
+func createGoreleaserDockerfile(dir string, ctx projectContext) error {
+	return renderFile(dir, GoreleaserDockerfile, templatesFS, GoreleaserDockerfileTemplate, ctx)
+}
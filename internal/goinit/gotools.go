@@ -0,0 +1,93 @@
+package goinit
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// GoToolDirectiveVersion is the first Go minor version supporting tool
+	// directives in go.mod.
+	GoToolDirectiveVersion = 24
+	ToolsFile              = "tools.go"
+	ToolsTemplate          = "templates/tools/tools.go.tmpl"
+	ToolsMakefileTemplate  = "templates/tools/tools.mk.tmpl"
+)
+
+type devTool struct {
+	Package string
+	Version string
+	Target  string
+	Args    string
+	Run     string
+}
+
+// devTools are the tools pinned by -tools, with the Make targets running
+// them. They are sorted by package so the generated tools.go is gofmt clean.
+var devTools = []devTool{
+	{Package: "github.com/golangci/golangci-lint/cmd/golangci-lint", Version: "v1.64.8", Target: "lint", Args: "run"},
+	{Package: "github.com/goreleaser/goreleaser", Version: "v1.26.2", Target: "snapshot", Args: "release --snapshot --clean"},
+	{Package: "github.com/segmentio/golines", Version: "v0.12.2", Target: "golines", Args: "-m 120 -w ."},
+	{Package: "github.com/vektra/mockery/v2", Version: "v2.46.0", Target: "mocks", Args: "--all"},
+}
+
+// goMinorVersion returns the minor version of the installed Go toolchain,
+// e.g. 22 for go1.22.5.
+func goMinorVersion() (int, error) {
+	out, err := commandOutput("", "go", "env", "GOVERSION")
+	if err != nil {
+		return 0, fmt.Errorf("error reading go version: %w", err)
+	}
+
+	version := strings.TrimPrefix(strings.TrimSpace(string(out)), "go1.")
+	minor, _, _ := strings.Cut(version, ".")
+
+	return strconv.Atoi(minor)
+}
+
+// createToolDependencies pins devTools with go.mod tool directives when the
+// toolchain supports them, and with a tools.go file otherwise.
+func createToolDependencies(dir string) error {
+	minor, err := goMinorVersion()
+	if err != nil {
+		return err
+	}
+
+	tools := make([]devTool, len(devTools))
+	copy(tools, devTools)
+
+	if minor >= GoToolDirectiveVersion {
+		args := []string{"get", "-tool"}
+		for i, tool := range tools {
+			args = append(args, tool.Package+"@"+tool.Version)
+			tools[i].Run = "go tool " + path.Base(strings.TrimSuffix(tool.Package, "/v2"))
+		}
+
+		if err := runNetworkCommand(dir, "go", args...); err != nil {
+			log.Printf("Could not add tool dependencies, run `go %s` in the project: %v", strings.Join(args, " "), err)
+		}
+	} else {
+		for i, tool := range tools {
+			tools[i].Run = "go run " + tool.Package
+		}
+
+		if err := renderFile(dir, ToolsFile, templatesFS, ToolsTemplate, tools); err != nil {
+			return fmt.Errorf("error creating %s: %w", ToolsFile, err)
+		}
+
+		for _, tool := range tools {
+			if err := runNetworkCommand(dir, "go", "get", tool.Package+"@"+tool.Version); err != nil {
+				log.Printf("Could not add %s, run `go get %s@%s` in the project: %v", tool.Package, tool.Package, tool.Version, err)
+			}
+		}
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, ToolsMakefileTemplate, tools); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package goinit
+
+import (
+	"fmt"
+	"log"
+)
+
+const (
+	HooksFlag               = "hooks"
+	HooksScript             = "script"
+	HooksPreCommit          = "pre-commit-framework"
+	HooksLefthook           = "lefthook"
+	PreCommitConfigTemplate = "templates/hooks/pre-commit-config.yaml"
+	PreCommitConfigFile     = ".pre-commit-config.yaml"
+	LefthookTemplate        = "templates/hooks/lefthook.yml"
+	LefthookFile            = "lefthook.yml"
+)
+
+// hookManager installs the pre-commit hook of a -hooks value, lists the
+// git configuration it sets for undo to remove and tells where the hook
+// went.
+type hookManager struct {
+	create    func(dir string) error
+	gitConfig []string
+	location  string
+}
+
+// hookManagers maps the -hooks values to the way the hook is installed: the
+// script in .githooks, or the configuration of the pre-commit framework or
+// lefthook.
+var hookManagers = map[string]hookManager{
+	HooksScript:    {createPreCommitHook, []string{"core.hooksPath"}, "is " + PreCommitHookFile + ", which git runs through core.hooksPath"},
+	HooksPreCommit: {createPreCommitConfig, nil, "is configured in " + PreCommitConfigFile + " and installed by `pre-commit install`"},
+	HooksLefthook:  {createLefthookConfig, nil, "is configured in " + LefthookFile + " and installed by `lefthook install`"},
+}
+
+// createPreCommitConfig writes the pre-commit framework's configuration and
+// installs its hook when pre-commit is installed.
+func createPreCommitConfig(dir string) error {
+	if err := createFile(dir, PreCommitConfigFile, templatesFS, PreCommitConfigTemplate); err != nil {
+		return fmt.Errorf("error creating %s: %w", PreCommitConfigFile, err)
+	}
+
+	return installHooks(dir, "pre-commit")
+}
+
+// createLefthookConfig writes the lefthook configuration and installs its
+// hooks when lefthook is installed.
+func createLefthookConfig(dir string) error {
+	if err := createFile(dir, LefthookFile, templatesFS, LefthookTemplate); err != nil {
+		return fmt.Errorf("error creating %s: %w", LefthookFile, err)
+	}
+
+	return installHooks(dir, "lefthook")
+}
+
+// installHooks runs the install command of a hook manager in dir. Without
+// the manager the hooks are installed later, by the setup script or by
+// hand.
+func installHooks(dir, manager string) error {
+	if _, err := commandOutput("", manager, "--help"); err != nil {
+		log.Printf("%s is not installed, run `%s install` once it is", manager, manager)
+		return nil
+	}
+
+	if err := runCommand(dir, manager, "install"); err != nil {
+		return fmt.Errorf("error installing the %s hooks: %w", manager, err)
+	}
+
+	return nil
+}
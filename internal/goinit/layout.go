@@ -0,0 +1,47 @@
+package goinit
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	LayoutOperator   = "operator"
+	LayoutTFProvider = "tf-provider"
+	LayoutGithubApp  = "github-app"
+	LayoutBot        = "bot"
+	LayoutCronJob    = "cronjob"
+	LayoutDesktop    = "desktop"
+	LayoutMobile     = "mobile"
+	LayoutMCP        = "mcp"
+	LayoutSSHApp     = "ssh-app"
+)
+
+// layouts maps the supported -layout values to the function generating
+// them. Every layout writes its own main.go.
+var layouts = map[string]func(dir string, opts options) error{
+	LayoutOperator:   createOperatorLayout,
+	LayoutTFProvider: createTFProviderLayout,
+	LayoutGithubApp:  createGithubAppLayout,
+	LayoutBot:        createBotLayout,
+	LayoutCronJob:    createCronJobLayout,
+	LayoutDesktop:    createDesktopLayout,
+	LayoutMobile:     createMobileLayout,
+	LayoutMCP:        createMCPLayout,
+	LayoutSSHApp:     createSSHAppLayout,
+}
+
+// renderFiles renders files with data, creating their directories.
+func renderFiles(dir string, files []templateFile, data any) error {
+	for _, file := range files {
+		if err := ensureDir(filepath.Join(dir, filepath.Dir(file.Name))); err != nil {
+			return err
+		}
+
+		if err := renderFile(dir, file.Name, templatesFS, file.Template, data); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// Package goinit implements the goinit command and the project generation
+// the pkg/scaffold package exposes to other tools.
+package goinit
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Options are the generation options of pkg/scaffold, one field for each
+// flag of goinit new. Zero values leave the flag's default. pkg/scaffold
+// converts its Options to these, so both have the same fields.
+type Options struct {
+	Name            string
+	Dir             string
+	Module          string
+	Type            string
+	License         string
+	Template        string
+	Vars            map[string]string
+	Layout          string
+	Router          string
+	Platform        string
+	Framework       string
+	FlagsPackage    string
+	DI              string
+	Mocks           string
+	DB              string
+	Migrations      string
+	GoVersion       string
+	Host            string
+	CI              string
+	Lint            string
+	Editor          string
+	Hooks           string
+	TaskRunner      string
+	Skip            []string
+	BuildTargets    []string
+	Release         string
+	ReleaseNotes    string
+	Changelog       string
+	Registry        string
+	BrewTap         string
+	Branch          string
+	ComposeServices []string
+	RateLimit       bool
+	CORS            bool
+	Assets          bool
+	I18n            bool
+	Automation      bool
+	Labels          bool
+	Protect         bool
+	NoGit           bool
+	Commit          bool
+	CreateRemote    bool
+	Private         bool
+	Push            bool
+	Provenance      bool
+	Buildx          bool
+	Docker          bool
+	Trivy           bool
+	AUR             bool
+	Debian          bool
+	RPM             bool
+	Chocolatey      bool
+	DockerImages    bool
+	Nfpm            bool
+	SupplyChain     bool
+	Tools           bool
+	Sops            bool
+	Environments    bool
+	K8s             bool
+	Compose         bool
+	Devcontainer    bool
+	NoReadme        bool
+	Workspace       bool
+	Community       bool
+}
+
+// Generate creates the project of o inside o.Dir, the working directory
+// when empty, running its git and go commands under ctx.
+func Generate(ctx context.Context, o Options) error {
+	opts, err := o.options()
+	if err != nil {
+		return err
+	}
+
+	dir := o.Dir
+	if dir == "" {
+		dir = "."
+	}
+
+	if dir, err = absPath(dir); err != nil {
+		return err
+	}
+
+	restore, err := useTemplates(o.Template)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if err := generateIn(ctx, dir, opts); err != nil {
+		return fmt.Errorf("error creating project: %w", err)
+	}
+
+	return nil
+}
+
+// options sets the options on the goinit flags, so they are recorded and
+// validated as the command line's are.
+func (o Options) options() (options, error) {
+	var opts options
+
+	set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	registerFlags(set, &opts)
+
+	opts.projectName = o.Name
+
+	for dst, value := range map[*string]string{
+		&opts.module:          o.Module,
+		&opts.projectType:     o.Type,
+		&opts.license:         o.License,
+		&opts.layout:          o.Layout,
+		&opts.router:          o.Router,
+		&opts.platform:        o.Platform,
+		&opts.framework:       o.Framework,
+		&opts.flags:           o.FlagsPackage,
+		&opts.di:              o.DI,
+		&opts.mocks:           o.Mocks,
+		&opts.db:              o.DB,
+		&opts.migrations:      o.Migrations,
+		&opts.goVersion:       o.GoVersion,
+		&opts.host:            o.Host,
+		&opts.ci:              o.CI,
+		&opts.lint:            o.Lint,
+		&opts.editor:          o.Editor,
+		&opts.hooks:           o.Hooks,
+		&opts.runner:          o.TaskRunner,
+		&opts.skip:            strings.Join(o.Skip, ","),
+		&opts.buildTargets:    strings.Join(o.BuildTargets, ","),
+		&opts.release:         o.Release,
+		&opts.releaseNotes:    o.ReleaseNotes,
+		&opts.changelog:       o.Changelog,
+		&opts.registry:        o.Registry,
+		&opts.brewTap:         o.BrewTap,
+		&opts.branch:          o.Branch,
+		&opts.composeServices: strings.Join(o.ComposeServices, ","),
+	} {
+		if value != "" {
+			*dst = value
+		}
+	}
+
+	opts.rateLimit = o.RateLimit
+	opts.cors = o.CORS
+	opts.assets = o.Assets
+	opts.i18n = o.I18n
+	opts.automation = o.Automation
+	opts.labels = o.Labels
+	opts.protect = o.Protect
+	opts.noGit = o.NoGit
+	opts.commit = o.Commit
+	opts.createRemote = o.CreateRemote
+	opts.private = o.Private
+	opts.push = o.Push
+	opts.provenance = o.Provenance
+	opts.buildx = o.Buildx
+	opts.docker = o.Docker
+	opts.trivy = o.Trivy
+	opts.aur = o.AUR
+	opts.debian = o.Debian
+	opts.rpm = o.RPM
+	opts.chocolatey = o.Chocolatey
+	opts.dockerImages = o.DockerImages
+	opts.nfpm = o.Nfpm
+	opts.supplyChain = o.SupplyChain
+	opts.tools = o.Tools
+	opts.sops = o.Sops
+	opts.environments = o.Environments
+	opts.k8s = o.K8s
+	opts.compose = o.Compose
+	opts.devcontainer = o.Devcontainer
+	opts.noReadme = o.NoReadme
+	opts.workspace = o.Workspace
+	opts.community = o.Community
+
+	for key, value := range o.Vars {
+		opts.vars[key] = value
+	}
+
+	opts.args = changedArgs(set)
+
+	return opts, opts.validate()
+}
@@ -0,0 +1,36 @@
+package goinit
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	LicenseFile       = "LICENSE"
+	ComponentMakefile = "makefile"
+	ComponentCI       = "ci"
+	ComponentHooks    = "hooks"
+)
+
+var licenseTemplates = map[string]string{
+	"mit":          "templates/licenses/mit.tmpl",
+	"apache-2.0":   "templates/licenses/apache-2.0.tmpl",
+	"bsd-3-clause": "templates/licenses/bsd-3-clause.tmpl",
+}
+
+type licenseData struct {
+	Year   string
+	Holder string
+}
+
+// createLicense writes the -license text with the current year and the git
+// user as the copyright holder.
+func createLicense(dir string, opts options) error {
+	info := newPackageInfo(dir, opts)
+	data := licenseData{
+		Year:   strconv.Itoa(buildTime().Year()),
+		Holder: strings.TrimSpace(strings.Split(info.Maintainer, "<")[0]),
+	}
+
+	return renderFile(dir, LicenseFile, templatesFS, licenseTemplates[opts.license], data)
+}
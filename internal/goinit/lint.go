@@ -0,0 +1,30 @@
+package goinit
+
+const (
+	LintFlag                 = "lint"
+	LintStrict               = "strict"
+	LintStandard             = "standard"
+	LintMinimal              = "minimal"
+	LintNone                 = "none"
+	StandardGolangciTemplate = "templates/lint/standard.golangci.yml"
+	MinimalGolangciTemplate  = "templates/lint/minimal.golangci.yml"
+)
+
+// lintPresets maps the -lint presets to their golangci-lint configuration.
+// None generates no configuration, so golangci-lint runs its defaults.
+var lintPresets = map[string]string{
+	LintStrict:   GolangciTemplate,
+	LintStandard: StandardGolangciTemplate,
+	LintMinimal:  MinimalGolangciTemplate,
+	LintNone:     "",
+}
+
+// createGolangci writes the golangci-lint configuration of the -lint
+// preset, unless it is none.
+func createGolangci(dir string, opts options) error {
+	if lintPresets[opts.lint] == "" {
+		return nil
+	}
+
+	return createFile(dir, GolangciFile, templatesFS, lintPresets[opts.lint])
+}
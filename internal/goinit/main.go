@@ -0,0 +1,1846 @@
+package goinit
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*
+var embeddedTemplates embed.FS
+
+// templatesFS is where the templates are read from: the embedded ones, with
+// -template the user's templates over them.
+var templatesFS fs.FS = embeddedTemplates
+
+const (
+	DefaultProjectName              = "new_project"
+	GolangciTemplate                = "templates/.golangci.yml"
+	GoreleaserTemplate              = "templates/.goreleaser.yml.tmpl"
+	GitignoreTemplate               = "templates/.gitignore"
+	GitattributesTemplate           = "templates/.gitattributes"
+	MakefileTemplate                = "templates/Makefile.tmpl"
+	ReleaserTemplate                = "templates/releaser.yml.tmpl"
+	CIWorkflowTemplate              = "templates/ci.yml.tmpl"
+	PreCommitHookTemplate           = "templates/scripts/pre-commit"
+	SetupScriptTemplate             = "templates/scripts/setup.sh"
+	CIBuildScriptTemplate           = "templates/scripts/cibuild.sh"
+	SetupPowerShellTemplate         = "templates/scripts/setup.ps1"
+	CIBuildPowerShellTemplate       = "templates/scripts/cibuild.ps1"
+	ConfigTemplate                  = "templates/internal/config/config.go.tmpl"
+	RateLimitConfigTemplate         = "templates/internal/config/ratelimit.go.tmpl"
+	RateLimitConfigTestTemplate     = "templates/internal/config/ratelimit_internal_test.go.tmpl"
+	RateLimitTemplate               = "templates/internal/middleware/ratelimit.go.tmpl"
+	RateLimitTestTemplate           = "templates/internal/middleware/ratelimit_internal_test.go.tmpl"
+	CORSConfigTemplate              = "templates/internal/config/cors.go.tmpl"
+	CORSConfigTestTemplate          = "templates/internal/config/cors_internal_test.go.tmpl"
+	CORSTemplate                    = "templates/internal/middleware/cors.go.tmpl"
+	CORSTestTemplate                = "templates/internal/middleware/cors_internal_test.go.tmpl"
+	WebEmbedTemplate                = "templates/web/embed.go.tmpl"
+	WebHandlerTemplate              = "templates/web/handler.go.tmpl"
+	WebHandlerTestTemplate          = "templates/web/handler_internal_test.go.tmpl"
+	WebIndexTemplate                = "templates/web/static/index.html"
+	WebAppTemplate                  = "templates/web/static/app.js"
+	WebSourceTemplate               = "templates/web/src/main.js"
+	AssetsScriptTemplate            = "templates/scripts/assets.sh"
+	AssetsMakefileTemplate          = "templates/snippets/assets.mk"
+	AssetsGitignoreTemplate         = "templates/snippets/assets.gitignore"
+	LocaleTemplate                  = "templates/internal/locale/locale.go.tmpl"
+	LocaleTestTemplate              = "templates/internal/locale/locale_internal_test.go.tmpl"
+	LocaleEnTemplate                = "templates/internal/locale/locales/active.en.json"
+	LocaleSvTemplate                = "templates/internal/locale/locales/active.sv.json"
+	I18nMakefileTemplate            = "templates/snippets/i18n.mk"
+	CLIStdlibTemplate               = "templates/cli/stdlib.go.tmpl"
+	CLIPflagTemplate                = "templates/cli/pflag.go.tmpl"
+	CLIUrfaveTemplate               = "templates/cli/urfave.go.tmpl"
+	CLIKongTemplate                 = "templates/cli/kong.go.tmpl"
+	StaleWorkflowTemplate           = "templates/github/stale.yml"
+	LabelerWorkflowTemplate         = "templates/github/labeler-workflow.yml"
+	LabelerConfigTemplate           = "templates/github/labeler.yml"
+	ReleaseDrafterTemplate          = "templates/github/release-drafter.yml"
+	ReleaseDrafterWorkflowTemplate  = "templates/github/release-drafter-workflow.yml"
+	DrafterGoreleaserTemplate       = "templates/snippets/drafter.goreleaser.yml"
+	SemanticReleaseConfigTemplate   = "templates/release/releaserc.json"
+	SemanticReleaseWorkflowTemplate = "templates/release/semantic-release.yml"
+	ProvenanceReleaserTemplate      = "templates/release/releaser-provenance.yml"
+	DockerfileTemplate              = "templates/docker/Dockerfile.tmpl"
+	DockerignoreTemplate            = "templates/docker/dockerignore"
+	DockerWorkflowTemplate          = "templates/docker/docker.yml"
+	DockerMakefileTemplate          = "templates/docker/docker.mk.tmpl"
+	TrivyWorkflowTemplate           = "templates/docker/trivy.yml"
+	ArtifactoryGoreleaserTemplate   = "templates/registry/artifactory.goreleaser.yml"
+	NexusGoreleaserTemplate         = "templates/registry/nexus.goreleaser.yml"
+	PublishMakefileTemplate         = "templates/registry/publish.mk"
+	DockerPublishMakefileTemplate   = "templates/registry/docker.mk"
+	AURGoreleaserTemplate           = "templates/release/aur.goreleaser.yml"
+	DebianControlTemplate           = "templates/debian/control.tmpl"
+	DebianRulesTemplate             = "templates/debian/rules.tmpl"
+	DebianChangelogTemplate         = "templates/debian/changelog.tmpl"
+	DebianFormatTemplate            = "templates/debian/source/format"
+	DebianMakefileTemplate          = "templates/debian/debian.mk"
+	RPMSpecTemplate                 = "templates/rpm/spec.tmpl"
+	RPMMakefileTemplate             = "templates/rpm/rpm.mk.tmpl"
+	RPMGitignoreTemplate            = "templates/rpm/rpm.gitignore"
+	ChocolateyGoreleaserTemplate    = "templates/release/chocolatey.goreleaser.yml"
+	NotifyTemplate                  = "templates/mocks/notify.go.tmpl"
+	NotifyTestTemplate              = "templates/mocks/notify_test.go.tmpl"
+	MockgenSenderTemplate           = "templates/mocks/mockgen_sender.go.tmpl"
+	MockerySenderTemplate           = "templates/mocks/mockery_sender.go.tmpl"
+	MockeryConfigTemplate           = "templates/mocks/mockery.yaml.tmpl"
+	GenerateMakefileTemplate        = "templates/mocks/generate.mk.tmpl"
+	ServerConfigTemplate            = "templates/di/server_config.go.tmpl"
+	ServerTemplate                  = "templates/di/server.go.tmpl"
+	LoggerTemplate                  = "templates/di/logger.go.tmpl"
+	WireProvidersTemplate           = "templates/di/wire_providers.go.tmpl"
+	WireInjectorTemplate            = "templates/di/wire.go.tmpl"
+	WireGenTemplate                 = "templates/di/wire_gen.go.tmpl"
+	WireMainTemplate                = "templates/di/wire_main.go.tmpl"
+	WireMakefileTemplate            = "templates/di/wire.mk"
+	FxModuleTemplate                = "templates/di/fx_module.go.tmpl"
+	FxMainTemplate                  = "templates/di/fx_main.go.tmpl"
+	SopsConfigTemplate              = "templates/sops/sops.yaml.tmpl"
+	SecretsTemplate                 = "templates/sops/app.yaml.tmpl"
+	SecretsDocTemplate              = "templates/sops/secrets.md"
+	SopsMakefileTemplate            = "templates/sops/sops.mk"
+	SopsGitignoreTemplate           = "templates/sops/sops.gitignore"
+	EnvironmentConfigTemplate       = "templates/internal/config/environment.go.tmpl"
+	EnvironmentConfigTestTemplate   = "templates/internal/config/environment_internal_test.go.tmpl"
+	BaseConfigTemplate              = "templates/configs/base.yaml"
+	DevConfigTemplate               = "templates/configs/dev.yaml"
+	StagingConfigTemplate           = "templates/configs/staging.yaml"
+	ProdConfigTemplate              = "templates/configs/prod.yaml"
+	GolangciFile                    = ".golangci.yml"
+	GoreleaserFile                  = ".goreleaser.yml"
+	GitignoreFile                   = ".gitignore"
+	GitattributesFile               = ".gitattributes"
+	GithubDir                       = ".github"
+	WorkflowsDir                    = ".github/workflows"
+	ReleaserFile                    = ".github/workflows/releaser.yml"
+	CIWorkflowFile                  = ".github/workflows/ci.yml"
+	StaleWorkflowFile               = ".github/workflows/stale.yml"
+	LabelerWorkflowFile             = ".github/workflows/labeler.yml"
+	LabelerConfigFile               = ".github/labeler.yml"
+	ReleaseDrafterFile              = ".github/release-drafter.yml"
+	ReleaseDrafterWorkflowFile      = ".github/workflows/release-drafter.yml"
+	SemanticReleaseConfigFile       = ".releaserc"
+	SemanticReleaseWorkflowFile     = ".github/workflows/release.yml"
+	DockerWorkflowFile              = ".github/workflows/docker.yml"
+	TrivyWorkflowFile               = ".github/workflows/trivy.yml"
+	Dockerfile                      = "Dockerfile"
+	DockerignoreFile                = ".dockerignore"
+	DebianControlFile               = "debian/control"
+	DebianRulesFile                 = "debian/rules"
+	DebianChangelogFile             = "debian/changelog"
+	DebianFormatFile                = "debian/source/format"
+	NotifyFile                      = "internal/notify/notify.go"
+	NotifyTestFile                  = "internal/notify/notify_test.go"
+	SenderMockFile                  = "internal/notify/mocks/sender.go"
+	MockeryConfigFile               = ".mockery.yaml"
+	ServerConfigFile                = "internal/config/server.go"
+	ServerFile                      = "internal/server/server.go"
+	LoggerFile                      = "internal/app/logger.go"
+	WireProvidersFile               = "internal/app/providers.go"
+	WireInjectorFile                = "internal/app/wire.go"
+	WireGenFile                     = "internal/app/wire_gen.go"
+	FxModuleFile                    = "internal/app/module.go"
+	SopsConfigFile                  = ".sops.yaml"
+	SecretsFile                     = "secrets/app.enc.yaml"
+	SecretsDecryptedFile            = "secrets/app.dec.yaml"
+	SecretsDocFile                  = "docs/secrets.md"
+	EnvironmentConfigFile           = "internal/config/environment.go"
+	EnvironmentConfigTestFile       = "internal/config/environment_internal_test.go"
+	BaseConfigFile                  = "configs/base.yaml"
+	DevConfigFile                   = "configs/dev.yaml"
+	StagingConfigFile               = "configs/staging.yaml"
+	ProdConfigFile                  = "configs/prod.yaml"
+	GitHooksDir                     = ".githooks"
+	ScriptsDir                      = "scripts"
+	SetupScriptFile                 = "scripts/setup.sh"
+	CIBuildScriptFile               = "scripts/cibuild.sh"
+	SetupPowerShellFile             = "scripts/setup.ps1"
+	CIBuildPowerShellFile           = "scripts/cibuild.ps1"
+	PreCommitHookFile               = ".githooks/pre-commit"
+	Makefile                        = "Makefile"
+	ConfigFile                      = "internal/config/config.go"
+	RateLimitConfigFile             = "internal/config/ratelimit.go"
+	RateLimitConfigTestFile         = "internal/config/ratelimit_internal_test.go"
+	RateLimitFile                   = "internal/middleware/ratelimit.go"
+	RateLimitTestFile               = "internal/middleware/ratelimit_internal_test.go"
+	CORSConfigFile                  = "internal/config/cors.go"
+	CORSConfigTestFile              = "internal/config/cors_internal_test.go"
+	CORSFile                        = "internal/middleware/cors.go"
+	CORSTestFile                    = "internal/middleware/cors_internal_test.go"
+	WebEmbedFile                    = "web/embed.go"
+	WebHandlerFile                  = "web/handler.go"
+	WebHandlerTestFile              = "web/handler_internal_test.go"
+	WebIndexFile                    = "web/static/index.html"
+	WebAppFile                      = "web/static/app.js"
+	WebSourceFile                   = "web/src/main.js"
+	AssetsScriptFile                = "scripts/assets.sh"
+	LocaleFile                      = "internal/locale/locale.go"
+	LocaleTestFile                  = "internal/locale/locale_internal_test.go"
+	LocaleEnFile                    = "internal/locale/locales/active.en.json"
+	LocaleSvFile                    = "internal/locale/locales/active.sv.json"
+	MainFile                        = "main.go"
+	StagingPrefix                   = ".goinit-"
+	NoGitFlag                       = "no-git"
+	CommitFlag                      = "commit"
+	BranchFlag                      = "branch"
+	DefaultBranch                   = "main"
+	InitialCommitMessage            = "initial scaffold"
+	GoVersionFlag                   = "go-version"
+	KeepPartialFlag                 = "keep-partial"
+	FlagsStdlib                     = "stdlib"
+	FlagsPflag                      = "pflag"
+	FlagsUrfave                     = "urfave"
+	FlagsKong                       = "kong"
+	ReleaseNotesGoreleaser          = "goreleaser"
+	ReleaseNotesDrafter             = "drafter"
+	ReleaseGoreleaser               = "goreleaser"
+	ReleaseSemantic                 = "semantic-release"
+	RegistryArtifactory             = "artifactory"
+	RegistryNexus                   = "nexus"
+	MocksMockery                    = "mockery"
+	MocksMockgen                    = "mockgen"
+	DIWire                          = "wire"
+	DIFx                            = "fx"
+)
+
+// registryTemplates maps the supported -registry values to the goreleaser
+// configuration uploading the release archives to them.
+var registryTemplates = map[string]string{
+	RegistryArtifactory: ArtifactoryGoreleaserTemplate,
+	RegistryNexus:       NexusGoreleaserTemplate,
+}
+
+// cliTemplates maps the supported -flags values to the command skeleton
+// generated for them.
+var cliTemplates = map[string]string{
+	FlagsStdlib: CLIStdlibTemplate,
+	FlagsPflag:  CLIPflagTemplate,
+	FlagsUrfave: CLIUrfaveTemplate,
+	FlagsKong:   CLIKongTemplate,
+}
+
+type options struct {
+	projectName  string
+	rateLimit    bool
+	cors         bool
+	assets       bool
+	i18n         bool
+	flags        string
+	automation   bool
+	releaseNotes string
+	release      string
+	labels       bool
+	protect      bool
+	noGit        bool
+	commit       bool
+	branch       string
+	createRemote bool
+	private      bool
+	push         bool
+	provenance   bool
+	buildx       bool
+	docker       bool
+	ci           string
+	goVersion    string
+	host         string
+	lint         string
+	editor       string
+	hooks        string
+	devcontainer bool
+	trivy        bool
+	registry     string
+	aur          bool
+	debian       bool
+	rpm          bool
+	chocolatey   bool
+	tools        bool
+	mocks        string
+	di           string
+	sops         bool
+	environments bool
+	layout       string
+	projectType  string
+	platform     string
+	k8s          bool
+	framework    string
+	router       string
+	module       string
+	license      string
+	skip         string
+	noReadme     bool
+	workspace    bool
+	community    bool
+	buildTargets string
+	runner       string
+	brewTap      string
+	dockerImages bool
+	nfpm         bool
+	supplyChain  bool
+	changelog    string
+	db           string
+	migrations   string
+	compose      bool
+	// composeServices are the comma separated -compose-services.
+	composeServices string
+	// mainPackage is the package path of the command of a project goinit
+	// did not generate, in place of the one the options imply.
+	mainPackage string
+	// vars are the template variables set with -set.
+	vars varsFlag
+	// args are the arguments the options were parsed from, recorded in
+	// the project's manifest.
+	args []string
+}
+
+// releaseSecrets returns the repository secrets the release workflow has to
+// pass to GoReleaser.
+func (o options) releaseSecrets() []string {
+	var secrets []string
+
+	if o.aur {
+		secrets = append(secrets, "AUR_KEY")
+	}
+
+	if o.chocolatey {
+		secrets = append(secrets, "CHOCOLATEY_API_KEY")
+	}
+
+	if o.brewTap != "" {
+		secrets = append(secrets, BrewTapSecret)
+	}
+
+	return secrets
+}
+
+// given reports whether the flag name was given rather than filled in,
+// as the options of a manifest fill in its module path.
+func (o options) given(name string) bool {
+	for _, arg := range o.args {
+		if strings.HasPrefix(arg, "-"+name+"=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o options) validate() error {
+	if err := checkProjectName(o.projectName); err != nil {
+		return err
+	}
+
+	if _, ok := cliTemplates[o.flags]; o.flags != "" && !ok {
+		return fmt.Errorf("unsupported flag library %q, use one of stdlib, pflag, urfave or kong", o.flags)
+	}
+
+	if o.releaseNotes != ReleaseNotesGoreleaser && o.releaseNotes != ReleaseNotesDrafter {
+		return fmt.Errorf("unsupported release notes generator %q, use goreleaser or drafter", o.releaseNotes)
+	}
+
+	if o.release != ReleaseGoreleaser && o.release != ReleaseSemantic {
+		return fmt.Errorf("unsupported release tool %q, use goreleaser or semantic-release", o.release)
+	}
+
+	if o.release == ReleaseSemantic && o.releaseNotes == ReleaseNotesDrafter {
+		return errors.New("semantic-release writes its own release notes and cannot be combined with release-drafter")
+	}
+
+	if o.provenance && o.release != ReleaseGoreleaser {
+		return errors.New("provenance is generated by the goreleaser release workflow, it cannot be used with semantic-release")
+	}
+
+	if o.trivy && !o.buildx {
+		return errors.New("trivy scans the project's Docker image, use it together with -buildx")
+	}
+
+	if _, ok := registryTemplates[o.registry]; o.registry != "" && !ok {
+		return fmt.Errorf("unsupported registry %q, use artifactory or nexus", o.registry)
+	}
+
+	if o.mocks != "" && o.mocks != MocksMockery && o.mocks != MocksMockgen {
+		return fmt.Errorf("unsupported mock generator %q, use mockery or mockgen", o.mocks)
+	}
+
+	if o.di != "" && o.di != DIWire && o.di != DIFx {
+		return fmt.Errorf("unsupported dependency injection framework %q, use wire or fx", o.di)
+	}
+
+	if _, ok := layouts[o.layout]; o.layout != "" && !ok {
+		return fmt.Errorf("unsupported layout %q, use operator, tf-provider, github-app, bot, cronjob, desktop, mobile, mcp or ssh-app", o.layout)
+	}
+
+	if o.k8s && o.layout != LayoutCronJob {
+		return errors.New("-k8s generates the Kubernetes manifests of -layout cronjob")
+	}
+
+	if o.layout == LayoutBot && o.platform != PlatformSlack && o.platform != PlatformDiscord {
+		return fmt.Errorf("unsupported bot platform %q, use -platform slack or discord", o.platform)
+	}
+
+	if o.platform != "" && o.layout != LayoutBot {
+		return errors.New("-platform selects the chat platform of -layout bot")
+	}
+
+	if o.module != "" {
+		if err := checkModulePath(o.module); err != nil {
+			return err
+		}
+	}
+
+	if o.workspace && o.ciProvider() != CIGithub && o.ciProvider() != CINone {
+		return fmt.Errorf("the %s CI configuration runs on the root module only, use -%s with -ci github", o.ciProvider(), WorkspaceFlag)
+	}
+
+	if o.buildTargets != "" {
+		if _, err := parsePlatforms(o.buildTargets); err != nil {
+			return err
+		}
+
+		if o.projectType == TypeLib {
+			return fmt.Errorf("-%s sets the platforms of the binaries, which -type lib does not build", BuildTargetsFlag)
+		}
+	}
+
+	if _, ok := taskRunners[o.taskRunner()]; !ok {
+		return fmt.Errorf("unsupported task runner %q, use make, task or just", o.runner)
+	}
+
+	if flags := o.makeOnlyFlags(); o.taskRunner() != TaskRunnerMake && len(flags) > 0 {
+		return fmt.Errorf("the targets of %s only exist for Make, use -%s make", strings.Join(flags, ", "), TaskRunnerFlag)
+	}
+
+	if flags := o.makeOnlyFlags(); o.skips(ComponentMakefile) && len(flags) > 0 {
+		return fmt.Errorf("the targets of %s are written to the Makefile, which -skip makefile leaves out", strings.Join(flags, ", "))
+	}
+
+	if o.brewTap != "" {
+		if err := checkBrewTap(o.brewTap); err != nil {
+			return err
+		}
+	}
+
+	if o.dockerImages && (o.provenance || o.release != ReleaseGoreleaser) {
+		return fmt.Errorf("-%s publishes the images from the releaser workflow, it cannot be used with -provenance or -release semantic-release", DockerImagesFlag)
+	}
+
+	if _, ok := changelogs[o.changelog]; !ok {
+		return fmt.Errorf("unsupported changelog %q, use release-please, git-cliff or none", o.changelog)
+	}
+
+	if o.changelog != ChangelogNone && (o.provenance || o.release != ReleaseGoreleaser || o.releaseNotes != ReleaseNotesGoreleaser) {
+		return fmt.Errorf("%s writes the release notes in the releaser workflow, it cannot be used with -provenance, -release semantic-release or -release-notes drafter", o.changelog)
+	}
+
+	if o.supplyChain && (o.provenance || o.release != ReleaseGoreleaser) {
+		return fmt.Errorf("-%s signs the release from the releaser workflow, it cannot be used with -provenance or -release semantic-release", SupplyChainFlag)
+	}
+
+	if platforms, _ := parsePlatforms(o.buildTargets); o.dockerImages && o.buildTargets != "" && len(linuxArchs(platforms)) == 0 {
+		return fmt.Errorf("-%s builds linux images, add a linux platform to -%s", DockerImagesFlag, BuildTargetsFlag)
+	}
+
+	if o.projectType == TypeLib && (o.brewTap != "" || o.dockerImages || o.nfpm || o.supplyChain) {
+		return fmt.Errorf("-%s, -%s, -%s and -%s release the binary, which -type lib does not build", BrewTapFlag, DockerImagesFlag, NfpmFlag, SupplyChainFlag)
+	}
+
+	if o.projectType == TypeLib && (o.docker || o.buildx || o.aur || o.chocolatey || o.debian || o.rpm) {
+		return errors.New("-docker, -buildx, -aur, -chocolatey, -debian and -rpm package the binary, which -type lib does not build")
+	}
+
+	// The module path a manifest records is filled in, so only a given
+	// -module conflicts with the recorded -host of diff and upgrade.
+	if o.given("module") && o.host != "" {
+		return errors.New("-host derives the module path, which -module gives, use one of them")
+	}
+
+	if _, ok := licenseTemplates[o.license]; o.license != "" && !ok {
+		return fmt.Errorf("unsupported license %q, use mit, apache-2.0 or bsd-3-clause", o.license)
+	}
+
+	for _, c := range strings.Split(o.skip, ",") {
+		if c = strings.TrimSpace(c); c != "" && c != ComponentMakefile && c != ComponentCI && c != ComponentHooks {
+			return fmt.Errorf("unknown component %q, -skip takes makefile, ci or hooks", c)
+		}
+	}
+
+	if _, ok := lintPresets[o.lint]; !ok {
+		return fmt.Errorf("unsupported lint preset %q, use strict, standard, minimal or none", o.lint)
+	}
+
+	if _, ok := hookManagers[o.hooks]; !ok {
+		return fmt.Errorf("unsupported hooks %q, use script, pre-commit-framework or lefthook", o.hooks)
+	}
+
+	if _, ok := editors[o.editor]; !ok {
+		return fmt.Errorf("unsupported editor %q, use vscode or none", o.editor)
+	}
+
+	if _, ok := ciProviders[o.ci]; o.ci != "" && !ok {
+		return fmt.Errorf("unsupported CI provider %q, use github, gitlab, bitbucket, circleci or none", o.ci)
+	}
+
+	if o.noGit && (o.commit || o.createRemote) {
+		return errors.New("-commit and -create-remote need the repository -no-git leaves out")
+	}
+
+	if o.push && (!o.commit || !o.createRemote) {
+		return errors.New("-push pushes the -commit to the repository of -create-remote, add both")
+	}
+
+	if o.private && !o.createRemote {
+		return errors.New("-private makes the repository of -create-remote private, add -create-remote")
+	}
+
+	if err := checkBranchName(o.branch); err != nil {
+		return err
+	}
+
+	if o.goVersion != "" && !goVersionPattern.MatchString(o.goVersion) {
+		return fmt.Errorf("invalid Go version %q, give one such as 1.22 or 1.22.5", o.goVersion)
+	}
+
+	if o.host != "" && (strings.Contains(o.host, "/") || checkModulePath(o.host) != nil) {
+		return fmt.Errorf("invalid host %q, give a host name such as gitlab.com", o.host)
+	}
+
+	if o.ciProvider() == CINone && (o.changesRelease() || o.layout == LayoutTFProvider || o.layout == LayoutDesktop) {
+		return errors.New("-skip ci and -ci none leave out the workflows that the release options and the layout change")
+	}
+
+	if p := o.ciProvider(); p != CIGithub && p != CINone && (o.release != ReleaseGoreleaser || o.releaseNotes != ReleaseNotesGoreleaser ||
+		o.provenance || o.automation || o.buildx || o.dockerImages || o.supplyChain || o.changelog != ChangelogNone || o.layout == LayoutTFProvider || o.layout == LayoutDesktop || o.layout == LayoutMobile) {
+		return fmt.Errorf("-release, -release-notes, -provenance, -automation, -buildx, -docker-images, -supply-chain, -changelog and the tf-provider, desktop and mobile layouts generate GitHub Actions workflows, they cannot be used with -ci %s", p)
+	}
+
+	if (o.layout == LayoutTFProvider || o.layout == LayoutDesktop) && o.changesRelease() {
+		return fmt.Errorf("the %s layout brings its own release configuration, it cannot be combined with other release options", o.layout)
+	}
+
+	if o.layout == LayoutDesktop && o.framework != FrameworkFyne && o.framework != FrameworkWails {
+		return fmt.Errorf("unsupported desktop framework %q, use -framework fyne or wails", o.framework)
+	}
+
+	if o.framework != "" && o.layout != LayoutDesktop {
+		return errors.New("-framework selects the GUI toolkit of -layout desktop")
+	}
+
+	if _, ok := projectTypes[o.projectType]; o.projectType != "" && !ok {
+		return fmt.Errorf("unsupported type %q, use cli, lib, api, grpc, worker or openapi", o.projectType)
+	}
+
+	if _, ok := apiRouters[o.router]; o.router != "" && !ok {
+		return fmt.Errorf("unsupported router %q, use stdlib, chi, echo or gin", o.router)
+	}
+
+	if o.router != "" && o.projectType != TypeAPI {
+		return errors.New("-router selects the router of -type api")
+	}
+
+	if _, ok := databases[o.db]; o.db != "" && !ok {
+		return fmt.Errorf("unsupported database %q, use postgres, sqlite or mysql", o.db)
+	}
+
+	if o.db != "" && o.projectType != TypeAPI {
+		return errors.New("-db connects the server of -type api to a database")
+	}
+
+	if o.migrations != "" && o.migrations != MigrationsGoose && o.migrations != MigrationsGolangMigrate {
+		return fmt.Errorf("unsupported migration tool %q, use goose or golang-migrate", o.migrations)
+	}
+
+	if o.migrations != "" && o.db == "" {
+		return errors.New("-migrations selects the migration tool of -db")
+	}
+
+	if err := o.validateCompose(); err != nil {
+		return err
+	}
+
+	if countSet(o.flags, o.di, o.layout, o.projectType) > 1 {
+		return errors.New("-flags, -di, -layout and -type each generate the project's code, use one of them")
+	}
+
+	return nil
+}
+
+// modulePath returns the -module path as given, without looking up the
+// GitHub user, or one derived from the project name and the user.
+func (o options) modulePath() string {
+	if o.module != "" {
+		return o.module
+	}
+
+	return modulePath(o.host, o.projectName)
+}
+
+// skips reports whether component was left out with -skip.
+func (o options) skips(component string) bool {
+	// The hooks are installed into the repository.
+	if component == ComponentHooks && o.noGit {
+		return true
+	}
+
+	for _, c := range strings.Split(o.skip, ",") {
+		if strings.TrimSpace(c) == component {
+			return true
+		}
+	}
+
+	return false
+}
+
+// changesRelease reports whether the default GoReleaser release is replaced
+// or extended.
+func (o options) changesRelease() bool {
+	return o.release != ReleaseGoreleaser || o.releaseNotes != ReleaseNotesGoreleaser ||
+		o.provenance || o.registry != "" || o.aur || o.chocolatey || o.brewTap != "" || o.dockerImages || o.nfpm || o.supplyChain ||
+		o.changelog != ChangelogNone
+}
+
+// countSet returns how many of values are not empty.
+func countSet(values ...string) int {
+	n := 0
+
+	for _, v := range values {
+		if v != "" {
+			n++
+		}
+	}
+
+	return n
+}
+
+// hasMain reports whether an option generates the project's code in place
+// of the starter command.
+func (o options) hasMain() bool {
+	return o.layout != "" || o.flags != "" || o.di != "" || o.projectType != ""
+}
+
+// hasDockerfile reports whether an option generates the Dockerfile, which
+// the layouts then build on rather than creating their own.
+func (o options) hasDockerfile() bool {
+	return o.buildx || o.docker
+}
+
+// hasDependencies reports whether the generated code imports modules that
+// have to be downloaded.
+func (o options) hasDependencies() bool {
+	return o.i18n || o.mocks != "" || o.di != "" || o.layout != "" || o.environments || (o.flags != "" && o.flags != FlagsStdlib) ||
+		o.projectType == TypeCLI || o.projectType == TypeGRPC || o.projectType == TypeOpenAPI || o.db != "" || (o.router != "" && o.router != RouterStdlib)
+}
+
+type templateFile struct {
+	Name     string
+	Template string
+}
+
+// Run runs the goinit command line with args, the arguments after the
+// program name. The error it returns is the one ReportError prints.
+func Run(args []string) error {
+	var err error
+	if userConfig, err = loadUserConfig(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	// Without a command, the arguments are the flags of new, as they were
+	// before goinit had commands.
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return ignoreHelp(newProject(args))
+	}
+
+	c, ok := findSubcommand(args[0])
+	if !ok {
+		printUsage(os.Stderr)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	if err := ignoreHelp(c.run(args[1:])); err != nil {
+		return fmt.Errorf("%s%w", c.failure, err)
+	}
+
+	return nil
+}
+
+// ignoreHelp drops the error of a -h flag, whose usage the flag set
+// printed.
+func ignoreHelp(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	return err
+}
+
+// registerFlags defines the generation options on fs. The web UI builds its
+// form from the same definitions.
+func registerFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.projectName, "d", DefaultProjectName, "project name")
+	fs.BoolVar(&opts.rateLimit, "ratelimit", false, "generate token-bucket rate limiting middleware")
+	fs.BoolVar(&opts.cors, "cors", false, "generate CORS middleware configured from the environment")
+	fs.BoolVar(&opts.assets, "assets", false, "generate an embedded web/ directory and static file handler")
+	fs.BoolVar(&opts.i18n, "i18n", false, "generate go-i18n message catalogs and translation Make targets")
+	fs.StringVar(&opts.flags, "flags", "", "generate a CLI main.go using stdlib, pflag, urfave or kong")
+	fs.BoolVar(&opts.automation, "automation", false, "generate stale issue and pull request labeler workflows")
+	fs.StringVar(&opts.releaseNotes, "release-notes", ReleaseNotesGoreleaser, "release notes generator: goreleaser or drafter")
+	fs.StringVar(&opts.release, "release", ReleaseGoreleaser, "release automation: goreleaser on tags or semantic-release")
+	fs.BoolVar(&opts.labels, "labels", false, "create standard labels and an initial milestone in the GitHub repository")
+	fs.BoolVar(&opts.protect, "protect", false, "protect the default branch of the GitHub repository")
+	fs.BoolVar(&opts.noGit, NoGitFlag, false, "generate the project without a git repository and pre-commit hook")
+	fs.BoolVar(&opts.commit, CommitFlag, false, "commit the generated project to the -branch")
+	fs.StringVar(&opts.branch, BranchFlag, DefaultBranch, "branch the -commit creates")
+	fs.BoolVar(&opts.createRemote, "create-remote", false, "create the GitHub repository of the module path and add it as origin")
+	fs.BoolVar(&opts.private, "private", false, "make the repository -create-remote creates private")
+	fs.BoolVar(&opts.push, "push", false, "push the -commit to the repository -create-remote creates")
+	fs.BoolVar(&opts.provenance, "provenance", false, "generate SLSA build provenance for released artifacts")
+	fs.BoolVar(&opts.buildx, "buildx", false, "generate a Dockerfile and a multi-arch buildx image workflow")
+	fs.BoolVar(&opts.docker, "docker", false, "generate a multi-stage Dockerfile, a .dockerignore and a docker Make target")
+	fs.BoolVar(&opts.trivy, "trivy", false, "generate a Trivy vulnerability scan workflow for the image and repository")
+	fs.BoolVar(&opts.aur, "aur", false, "publish a -bin package to the AUR with goreleaser")
+	fs.StringVar(&opts.registry, "registry", "", "publish release archives (and images with -buildx) to artifactory or nexus")
+	fs.BoolVar(&opts.debian, "debian", false, "generate a debian/ packaging directory")
+	fs.BoolVar(&opts.rpm, "rpm", false, "generate an RPM .spec file")
+	fs.BoolVar(&opts.chocolatey, "chocolatey", false, "publish a Chocolatey package with goreleaser")
+	fs.StringVar(&opts.changelog, ChangelogFlag, ChangelogNone, "changelog tool of the release workflow: release-please, git-cliff or none")
+	fs.StringVar(&opts.brewTap, BrewTapFlag, "", "publish a Homebrew formula to the owner/repo tap with goreleaser")
+	fs.BoolVar(&opts.dockerImages, DockerImagesFlag, false, "publish multi-arch Docker images of the released binary with goreleaser")
+	fs.BoolVar(&opts.nfpm, NfpmFlag, false, "publish .deb, .rpm and .apk packages with goreleaser")
+	fs.BoolVar(&opts.supplyChain, SupplyChainFlag, false, "sign the release keylessly with cosign and publish syft SBOMs with goreleaser")
+	fs.BoolVar(&opts.tools, "tools", false, "pin golangci-lint, goreleaser, mockery and golines in go.mod")
+	fs.StringVar(&opts.mocks, "mocks", "", "generate an example interface and mock with mockery or mockgen")
+	fs.StringVar(&opts.di, "di", "", "generate a server wired with wire or fx dependency injection")
+	fs.BoolVar(&opts.sops, "sops", false, "generate sops/age encrypted secrets and Make targets to edit them")
+	fs.BoolVar(&opts.environments, "environments", false, "generate dev, staging and prod YAML configs loaded by the config package")
+	fs.StringVar(&opts.layout, "layout", "", "generate a project layout: operator, tf-provider, github-app, bot, cronjob, desktop, mobile, mcp or ssh-app")
+	fs.StringVar(&opts.projectType, "type", "", "generate a project archetype: cli, lib, api, grpc, worker or openapi")
+	fs.StringVar(&opts.router, "router", "", "router of -type api: stdlib, chi, echo or gin, stdlib when empty")
+	fs.StringVar(&opts.db, DBFlag, "", "database of -type api, with migrations and a docker-compose.yml: postgres, sqlite or mysql")
+	fs.StringVar(&opts.migrations, MigrationsFlag, "", "migration tool of -db: goose or golang-migrate, goose when empty")
+	fs.BoolVar(&opts.compose, ComposeFlag, false, "generate a docker-compose.yml running the app from its Dockerfile, with up and down targets")
+	fs.StringVar(&opts.composeServices, ComposeServicesFlag, "", "comma separated services -compose runs next to the app: postgres and redis")
+	fs.StringVar(&opts.platform, "platform", "", "chat platform of the bot layout: slack or discord")
+	fs.BoolVar(&opts.k8s, "k8s", false, "generate Kubernetes manifests for the layout")
+	fs.StringVar(&opts.framework, "framework", "", "GUI toolkit of the desktop layout: fyne or wails")
+	fs.StringVar(&opts.goVersion, GoVersionFlag, "", "Go version of the go directive, the CI and release workflows and the Docker image, the installed toolchain's when empty")
+	fs.StringVar(&opts.module, "module", "", "module path of go.mod, derived when empty from the host's user in git config, the origin remote, gh or the SSH config")
+	fs.StringVar(&opts.license, "license", "", "generate a LICENSE: mit, apache-2.0 or bsd-3-clause")
+	fs.StringVar(&opts.ci, CIFlag, "", "CI provider of the test, lint and release configuration: github, gitlab, bitbucket, circleci or none, by default the host's")
+	fs.StringVar(&opts.host, HostFlag, "", "forge hosting the project, such as gitlab.com or git.example.com, which derived module paths start with")
+	fs.StringVar(&opts.lint, LintFlag, LintStrict, "golangci-lint configuration preset: strict, standard, minimal or none")
+	fs.StringVar(&opts.editor, EditorFlag, EditorNone, "editor settings to generate next to the .editorconfig: vscode or none")
+	fs.BoolVar(&opts.devcontainer, "devcontainer", false, "generate a dev container with the project's Go version and golangci-lint")
+	fs.StringVar(&opts.hooks, HooksFlag, HooksScript, "pre-commit hook: a script in .githooks, or a pre-commit-framework or lefthook configuration")
+	fs.StringVar(&opts.skip, "skip", "", "comma separated components to leave out: makefile, ci, hooks")
+	fs.StringVar(&opts.runner, TaskRunnerFlag, TaskRunnerMake, "task runner of the build, test and release targets: make, task (a Taskfile.yml) or just (a justfile)")
+	fs.BoolVar(&opts.noReadme, NoReadmeFlag, false, "leave out the generated README.md")
+	fs.BoolVar(&opts.community, CommunityFlag, false, "generate a CODEOWNERS, issue and pull request templates and a CONTRIBUTING.md")
+	fs.StringVar(&opts.buildTargets, BuildTargetsFlag, "", "comma separated GOOS/GOARCH pairs the Makefile cross-compiles for and GoReleaser builds, such as linux/amd64,darwin/arm64")
+	fs.BoolVar(&opts.workspace, WorkspaceFlag, false, "make the project the root module of a go.work, which goinit add module adds modules to")
+
+	opts.vars = varsFlag{}
+	fs.Var(opts.vars, SetFlag, "set a template variable as key=value, repeatable")
+}
+
+func isGoInstalled() bool {
+	_, err := commandOutput("", "go", "version")
+	return err == nil
+}
+
+func isGitInstalled() bool {
+	_, err := commandOutput("", "git", "version")
+	return err == nil
+}
+
+func mkdir(name string) error {
+	if _, err := os.Stat(name); err == nil {
+		return fmt.Errorf("folder already exists: %w", err)
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current working directory: %w", err)
+	}
+
+	path := filepath.Join(pwd, name)
+	if err = os.Mkdir(path, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating folder: %w", err)
+	}
+
+	return nil
+}
+
+// keepPartial keeps the staging directory of a failed generation, for
+// finding out what went wrong.
+var keepPartial bool
+
+// generateProject creates the project inside dir, in a staging directory
+// next to it that is renamed into place once complete, so the project
+// directory never holds a partly generated project. A failed or
+// interrupted generation leaves nothing behind, unless -keep-partial.
+func generateProject(dir string, opts options) (err error) {
+	if dir, err = absPath(dir); err != nil {
+		return err
+	}
+
+	project := filepath.Join(dir, opts.projectName)
+	if _, err := os.Stat(project); err == nil {
+		return fmt.Errorf("folder %s already exists", opts.projectName)
+	}
+
+	if !opts.noGit && !isGitInstalled() {
+		return errors.New("git is not installed, install it or add -no-git to generate the project without a repository")
+	}
+
+	// The staging directory is created in the same directory, as renaming
+	// is only atomic within a file system.
+	staging, err := os.MkdirTemp(dir, StagingPrefix+opts.projectName+"-")
+	if err != nil {
+		return fmt.Errorf("error creating staging directory: %w", err)
+	}
+
+	defer func() {
+		if err != nil && keepPartial {
+			log.Printf("Kept the partial project in %s", staging)
+			return
+		}
+
+		os.RemoveAll(staging)
+	}()
+
+	if err := os.Chmod(staging, 0o755); err != nil {
+		return fmt.Errorf("error creating staging directory: %w", err)
+	}
+
+	if err := createProjectFiles(staging, opts); err != nil {
+		return fmt.Errorf("error creating project files: %w", err)
+	}
+
+	if err := os.Rename(staging, project); err != nil {
+		return fmt.Errorf("error moving the project into place: %w", err)
+	}
+
+	return nil
+}
+
+// createProjectFiles generates the project in dir.
+func createProjectFiles(dir string, opts options) error {
+	projectName := opts.projectName
+	templateVars = opts.vars
+	filesToCreate := []templateFile{
+		{GitignoreFile, GitignoreTemplate},
+		{GitattributesFile, GitattributesTemplate},
+		{EditorconfigFile, EditorconfigTemplate},
+	}
+	filesToRender := []templateFile{
+		{GoreleaserFile, GoreleaserTemplate},
+	}
+
+	// Without the task runner's file, the options add no targets either.
+	if runner := taskRunners[opts.taskRunner()]; !opts.skips(ComponentMakefile) {
+		filesToRender = append(filesToRender, templateFile{runner.file, runner.template})
+	}
+
+	// Without Go, as in an image the toolchain is installed into later,
+	// the project is generated without its go.mod.
+	goInstalled := isGoInstalled()
+	if !goInstalled && opts.tools {
+		return errors.New("-tools pins the tools with the installed Go toolchain, install Go first")
+	}
+
+	if !opts.noGit {
+		steps.start("Initializing git repository")
+
+		if err := runCommand(dir, "git", "init"); err != nil {
+			return fmt.Errorf("error initializing repository: %w", err)
+		}
+	}
+
+	if goInstalled {
+		steps.start("Initializing Go module")
+
+		if err := goModInit(dir, opts.modulePath()); err != nil {
+			return fmt.Errorf("error initializing Go module: %w", err)
+		}
+
+		if opts.goVersion != "" {
+			if err := runCommand(dir, "go", "mod", "edit", "-go="+opts.goVersion); err != nil {
+				return fmt.Errorf("error setting the go directive: %w", err)
+			}
+		}
+	}
+
+	steps.start("Writing project files")
+
+	for _, file := range filesToCreate {
+		if err := createFile(dir, file.Name, templatesFS, file.Template); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	if err := createGolangci(dir, opts); err != nil {
+		return fmt.Errorf("error creating %s: %w", GolangciFile, err)
+	}
+
+	if err := editors[opts.editor](dir, opts); err != nil {
+		return fmt.Errorf("error creating the %s settings: %w", opts.editor, err)
+	}
+
+	// The context reads the go directive go mod init wrote.
+	ctx := newProjectContext(dir, opts)
+
+	for _, file := range filesToRender {
+		if err := renderFile(dir, file.Name, templatesFS, file.Template, ctx); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	if opts.workspace {
+		if err := createWorkspace(dir, ctx); err != nil {
+			return fmt.Errorf("error creating %s: %w", GoWorkFile, err)
+		}
+	}
+
+	if opts.projectType != "" {
+		if err := projectTypes[opts.projectType](dir, ctx); err != nil {
+			return fmt.Errorf("error creating %s project: %w", opts.projectType, err)
+		}
+	} else if !opts.hasMain() {
+		if err := createStarter(dir, ctx); err != nil {
+			return fmt.Errorf("error creating starter command: %w", err)
+		}
+	}
+
+	if err := createScripts(dir); err != nil {
+		return fmt.Errorf("error creating scripts: %w", err)
+	}
+
+	if err := ciProviders[opts.ciProvider()](dir, opts); err != nil {
+		return fmt.Errorf("error creating %s CI configuration: %w", opts.ciProvider(), err)
+	}
+
+	if opts.license != "" {
+		if err := createLicense(dir, opts); err != nil {
+			return fmt.Errorf("error creating %s: %w", LicenseFile, err)
+		}
+	}
+
+	if opts.automation {
+		if err := createRepositoryAutomation(dir); err != nil {
+			return fmt.Errorf("error creating repository automation: %w", err)
+		}
+	}
+
+	if opts.community {
+		if err := createGithubCommunity(dir, opts); err != nil {
+			return fmt.Errorf("error creating the GitHub community files: %w", err)
+		}
+	}
+
+	if opts.buildx {
+		if err := createDockerBuild(dir, opts); err != nil {
+			return fmt.Errorf("error creating docker build: %w", err)
+		}
+	}
+
+	if opts.docker {
+		if err := createDockerImage(dir, opts); err != nil {
+			return fmt.Errorf("error creating docker image: %w", err)
+		}
+	}
+
+	// The API runs in a container, which -docker and -buildx may have
+	// created already.
+	if opts.projectType == TypeAPI || opts.projectType == TypeOpenAPI {
+		if err := createAPIContainer(dir, opts); err != nil {
+			return fmt.Errorf("error creating the API's container: %w", err)
+		}
+	}
+
+	if opts.db != "" {
+		if err := createDatabase(dir, opts); err != nil {
+			return fmt.Errorf("error creating the %s database: %w", opts.db, err)
+		}
+	}
+
+	if opts.hasComposeFile() {
+		if err := createCompose(dir, opts); err != nil {
+			return fmt.Errorf("error creating the development environment: %w", err)
+		}
+	}
+
+	if opts.devcontainer {
+		if err := createDevcontainer(dir, opts); err != nil {
+			return fmt.Errorf("error creating dev container: %w", err)
+		}
+	}
+
+	if opts.trivy {
+		if err := createFile(dir, TrivyWorkflowFile, templatesFS, TrivyWorkflowTemplate); err != nil {
+			return fmt.Errorf("error creating %s: %w", TrivyWorkflowFile, err)
+		}
+	}
+
+	if opts.registry != "" {
+		if err := createRegistryPublishing(dir, opts); err != nil {
+			return fmt.Errorf("error creating registry publishing: %w", err)
+		}
+	}
+
+	if opts.aur {
+		if err := appendFile(dir, GoreleaserFile, templatesFS, AURGoreleaserTemplate); err != nil {
+			return fmt.Errorf("error updating %s: %w", GoreleaserFile, err)
+		}
+	}
+
+	if opts.dockerImages {
+		if err := createGoreleaserDockerfile(dir, newProjectContext(dir, opts)); err != nil {
+			return fmt.Errorf("error creating %s: %w", GoreleaserDockerfile, err)
+		}
+	}
+
+	if opts.debian {
+		if err := createDebianPackaging(dir, newPackageInfo(dir, opts)); err != nil {
+			return fmt.Errorf("error creating debian packaging: %w", err)
+		}
+	}
+
+	if opts.rpm {
+		if err := createRPMSpec(dir, newPackageInfo(dir, opts)); err != nil {
+			return fmt.Errorf("error creating rpm spec: %w", err)
+		}
+	}
+
+	if opts.chocolatey {
+		if err := createChocolateyPackage(dir); err != nil {
+			return fmt.Errorf("error creating chocolatey package: %w", err)
+		}
+	}
+
+	if opts.releaseNotes == ReleaseNotesDrafter {
+		if err := createReleaseDrafter(dir); err != nil {
+			return fmt.Errorf("error creating release drafter: %w", err)
+		}
+	}
+
+	if err := changelogs[opts.changelog](dir, opts); err != nil {
+		return fmt.Errorf("error creating the %s configuration: %w", opts.changelog, err)
+	}
+
+	if opts.rateLimit {
+		if err := createRateLimitMiddleware(dir); err != nil {
+			return fmt.Errorf("error creating rate limiting middleware: %w", err)
+		}
+	}
+
+	if opts.cors {
+		if err := createCORSMiddleware(dir); err != nil {
+			return fmt.Errorf("error creating CORS middleware: %w", err)
+		}
+	}
+
+	if opts.assets {
+		if err := createWebAssets(dir); err != nil {
+			return fmt.Errorf("error creating web assets: %w", err)
+		}
+	}
+
+	if opts.i18n {
+		if err := createLocales(dir); err != nil {
+			return fmt.Errorf("error creating locales: %w", err)
+		}
+	}
+
+	if opts.environments {
+		if err := createEnvironmentConfigs(dir); err != nil {
+			return fmt.Errorf("error creating environment configs: %w", err)
+		}
+	}
+
+	if opts.sops {
+		if err := createSopsSecrets(dir, projectName); err != nil {
+			return fmt.Errorf("error creating secrets: %w", err)
+		}
+	}
+
+	if opts.layout != "" {
+		if err := layouts[opts.layout](dir, opts); err != nil {
+			return fmt.Errorf("error creating %s layout: %w", opts.layout, err)
+		}
+	}
+
+	if opts.flags != "" {
+		if err := createFile(dir, MainFile, templatesFS, cliTemplates[opts.flags]); err != nil {
+			return fmt.Errorf("error creating %s: %w", MainFile, err)
+		}
+	}
+
+	if opts.di != "" {
+		if err := createDependencyInjection(dir, opts.di, opts.modulePath()); err != nil {
+			return fmt.Errorf("error creating dependency injection: %w", err)
+		}
+	}
+
+	if opts.mocks != "" {
+		if err := createMocks(dir, opts.mocks, opts.modulePath()); err != nil {
+			return fmt.Errorf("error creating mocks: %w", err)
+		}
+	}
+
+	if opts.tools {
+		if err := createToolDependencies(dir); err != nil {
+			return fmt.Errorf("error creating tool dependencies: %w", err)
+		}
+	}
+
+	if !opts.noReadme {
+		if err := createReadme(dir, opts); err != nil {
+			return fmt.Errorf("error creating %s: %w", ReadmeFile, err)
+		}
+	}
+
+	if opts.hasDependencies() && goInstalled {
+		steps.start("Downloading dependencies")
+		downloadDependencies(dir)
+	}
+
+	// Downloads only warn when they fail, but an interrupt stops here.
+	if rootCtx.Err() != nil {
+		return errInterrupted
+	}
+
+	if !opts.skips(ComponentHooks) {
+		steps.start("Installing pre-commit hook")
+
+		if err := hookManagers[opts.hooks].create(dir); err != nil {
+			return fmt.Errorf("error creating pre-commit hook: %w", err)
+		}
+	}
+
+	if err := createManifest(dir, opts); err != nil {
+		return fmt.Errorf("error creating %s: %w", ManifestFile, err)
+	}
+
+	if opts.commit {
+		steps.start("Committing to " + opts.branch)
+
+		if err := commitProject(dir, opts.branch); err != nil {
+			log.Printf("Could not commit the project: %v", err)
+		}
+	}
+
+	if opts.createRemote {
+		steps.start("Creating the GitHub repository")
+
+		err := createRemote(dir, opts.modulePath(), opts.private)
+		if err != nil {
+			log.Printf("Could not create the GitHub repository: %v", err)
+		}
+
+		if err == nil && opts.push {
+			steps.start("Pushing to " + GitRemote)
+
+			if err := runNetworkCommand(dir, "git", "push", "-u", GitRemote, opts.branch); err != nil {
+				log.Printf("Could not push to %s: %v", GitRemote, err)
+			}
+		}
+	}
+
+	// The project is complete at this point, so a repository that is not
+	// reachable yet only needs the labels created later.
+	if opts.labels {
+		steps.start("Creating GitHub labels")
+
+		if err := bootstrapLabels(opts.modulePath()); err != nil {
+			log.Printf("Could not create GitHub labels: %v", err)
+		}
+	}
+
+	if opts.protect {
+		steps.start("Protecting the default branch")
+
+		if err := protectDefaultBranch(opts.modulePath(), requiredChecks(dir, opts)); err != nil {
+			log.Printf("Could not protect the default branch: %v", err)
+		}
+	}
+
+	if !goInstalled {
+		init := "go mod init " + opts.modulePath()
+		if opts.goVersion != "" {
+			init += " && go mod edit -go=" + opts.goVersion
+		}
+
+		log.Printf("Go is not installed, so %s has no go.mod yet. Once it is, run `%s && go mod tidy` in it", projectName, init)
+	}
+
+	steps.finish()
+
+	return nil
+}
+
+func goModInit(dir, module string) error {
+	return runCommand(dir, "go", "mod", "init", module)
+}
+
+// commitProject commits everything generated to branch. The pre-commit
+// hook is skipped, as the linters it runs may not be installed yet.
+func commitProject(dir, branch string) error {
+	if err := runCommand(dir, "git", "add", "-A"); err != nil {
+		return fmt.Errorf("error staging the project: %w", err)
+	}
+
+	if err := runCommand(dir, "git", "commit", "--no-verify", "-m", InitialCommitMessage); err != nil {
+		return fmt.Errorf("error committing, check git's user.name and user.email: %w", err)
+	}
+
+	if err := runCommand(dir, "git", "branch", "-M", branch); err != nil {
+		return fmt.Errorf("error naming the branch %s: %w", branch, err)
+	}
+
+	return nil
+}
+
+// checkBranchName rejects the branch names git does, short of the rarer
+// rules git check-ref-format applies.
+func checkBranchName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("branch name is empty")
+	case strings.HasPrefix(name, "-"), strings.HasPrefix(name, "/"), strings.HasSuffix(name, "/"),
+		strings.HasSuffix(name, "."), strings.HasSuffix(name, ".lock"), strings.Contains(name, ".."),
+		strings.Contains(name, "//"), strings.Contains(name, "@{"), strings.ContainsAny(name, " ~^:?*[\\"):
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+
+	return nil
+}
+
+// goVersionPattern matches the Go versions -go-version takes, the language
+// version of a go directive with an optional patch version.
+var goVersionPattern = regexp.MustCompile(`^1\.\d+(\.\d+)?$`)
+
+// checkModulePath applies the rules of the go command to a module path:
+// slash separated elements of letters, digits and "-._~", none starting or
+// ending with a dot, nor a name Windows reserves. A first element with a
+// dot is a host name, which has to be lowercase.
+func checkModulePath(path string) error {
+	if path == "" {
+		return errors.New("module path is empty")
+	}
+
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid module path %q: %s", path, reason)
+	}
+
+	for i, elem := range strings.Split(path, "/") {
+		if elem == "" {
+			return invalid("empty path element, check for a leading, trailing or double slash")
+		}
+
+		if problem := pathElementProblem(elem); problem != "" {
+			return invalid(fmt.Sprintf("element %q %s", elem, problem))
+		}
+
+		if i == 0 && strings.Contains(elem, ".") && (elem != strings.ToLower(elem) || strings.HasPrefix(elem, "-")) {
+			return invalid(fmt.Sprintf("host %q has to be lowercase and cannot start with a dash", elem))
+		}
+	}
+
+	return nil
+}
+
+// downloadDependencies resolves the modules imported by the generated code.
+// Failing is not fatal, as the project is still usable once the user runs
+// `go mod tidy` with network access.
+func downloadDependencies(dir string) {
+	if err := runNetworkCommand(dir, "go", "mod", "tidy"); err != nil {
+		log.Printf("Could not download dependencies, run `go mod tidy` in the project: %v", err)
+	}
+}
+
+// createFile copies the template filePath of fsys to name, a path relative
+// to the project directory dir. The other file helpers take their names
+// the same way.
+func createFile(dir, name string, fsys fs.FS, filePath string) error {
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer file.Close()
+
+	bytes, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return fmt.Errorf("error reading template: %w", err)
+	}
+
+	_, err = file.Write(bytes)
+	if err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	logs.file(name)
+
+	return nil
+}
+
+func renderTemplate(fsys fs.FS, filePath string, data any) ([]byte, error) {
+	bytes, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template: %w", err)
+	}
+
+	tmpl, err := template.New(filePath).Funcs(templateFuncs()).Parse(string(bytes))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %w", err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("error rendering template: %w", err)
+	}
+
+	return []byte(buf.String()), nil
+}
+
+func renderFile(dir, name string, fsys fs.FS, filePath string, data any) error {
+	bytes, err := renderTemplate(fsys, filePath, data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), bytes, 0o666); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	logs.file(name)
+
+	return nil
+}
+
+func appendFile(dir, name string, fsys fs.FS, filePath string) error {
+	bytes, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return fmt.Errorf("error reading template: %w", err)
+	}
+
+	return appendBytes(dir, name, bytes)
+}
+
+func appendRenderedFile(dir, name string, fsys fs.FS, filePath string, data any) error {
+	bytes, err := renderTemplate(fsys, filePath, data)
+	if err != nil {
+		return err
+	}
+
+	return appendBytes(dir, name, bytes)
+}
+
+func appendBytes(dir, name string, bytes []byte) error {
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(bytes); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	logs.file(name)
+
+	return nil
+}
+
+// createPreCommitHook writes the hook to a committed directory and points
+// git at it, so the hook is versioned with the project.
+func createPreCommitHook(dir string) error {
+	if err := ensureDir(filepath.Join(dir, GitHooksDir)); err != nil {
+		return err
+	}
+
+	if err := createExecutableFile(dir, PreCommitHookFile, templatesFS, PreCommitHookTemplate); err != nil {
+		return fmt.Errorf("error creating %s: %w", PreCommitHookFile, err)
+	}
+
+	if err := runCommand(dir, "git", "config", "core.hooksPath", GitHooksDir); err != nil {
+		return fmt.Errorf("error setting core.hooksPath: %w", err)
+	}
+
+	return nil
+}
+
+func createGithubAction(dir string, opts options) error {
+	if err := ensureDir(filepath.Join(dir, WorkflowsDir)); err != nil {
+		return fmt.Errorf("error creating %s: %w", WorkflowsDir, err)
+	}
+
+	if err := renderFile(dir, CIWorkflowFile, templatesFS, CIWorkflowTemplate, newProjectContext(dir, opts)); err != nil {
+		return fmt.Errorf("error creating %s: %w", CIWorkflowFile, err)
+	}
+
+	// semantic-release tags and releases from its own workflow, so the
+	// tag triggered releaser would only run GoReleaser a second time.
+	if opts.release == ReleaseSemantic {
+		err := createFiles(dir, []templateFile{
+			{SemanticReleaseConfigFile, SemanticReleaseConfigTemplate},
+			{SemanticReleaseWorkflowFile, SemanticReleaseWorkflowTemplate},
+		})
+		if err != nil {
+			return err
+		}
+
+		return addWorkflowSecrets(dir, SemanticReleaseWorkflowFile, opts.releaseSecrets())
+	}
+
+	if opts.provenance {
+		if err := createFile(dir, ReleaserFile, templatesFS, ProvenanceReleaserTemplate); err != nil {
+			return fmt.Errorf("error creating %s: %w", ReleaserFile, err)
+		}
+	} else if err := renderFile(dir, ReleaserFile, templatesFS, ReleaserTemplate, newProjectContext(dir, opts)); err != nil {
+		return fmt.Errorf("error creating %s: %w", ReleaserFile, err)
+	}
+
+	return addWorkflowSecrets(dir, ReleaserFile, opts.releaseSecrets())
+}
+
+// addWorkflowSecrets exposes the named repository secrets as environment
+// variables next to GITHUB_TOKEN in the workflow file.
+func addWorkflowSecrets(dir, name string, secrets []string) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	bytes, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", name, err)
+	}
+
+	re := regexp.MustCompile(`(?m)^(\s+)GITHUB_TOKEN: .*$`)
+
+	workflow := re.ReplaceAllStringFunc(string(bytes), func(line string) string {
+		indent := re.FindStringSubmatch(line)[1]
+		for _, secret := range secrets {
+			line += fmt.Sprintf("\n%s%s: ${{ secrets.%s }}", indent, secret, secret)
+		}
+
+		return line
+	})
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(workflow), 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func createRepositoryAutomation(dir string) error {
+	return createFiles(dir, []templateFile{
+		{StaleWorkflowFile, StaleWorkflowTemplate},
+		{LabelerWorkflowFile, LabelerWorkflowTemplate},
+		{LabelerConfigFile, LabelerConfigTemplate},
+	})
+}
+
+func createReleaseDrafter(dir string) error {
+	err := createFiles(dir, []templateFile{
+		{ReleaseDrafterFile, ReleaseDrafterTemplate},
+		{ReleaseDrafterWorkflowFile, ReleaseDrafterWorkflowTemplate},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GoreleaserFile, templatesFS, DrafterGoreleaserTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GoreleaserFile, err)
+	}
+
+	return nil
+}
+
+func createDockerBuild(dir string, opts options) error {
+	if err := createDockerfile(dir, opts); err != nil {
+		return err
+	}
+
+	return createFiles(dir, []templateFile{{DockerWorkflowFile, DockerWorkflowTemplate}})
+}
+
+// createDockerImage writes the Dockerfile, unless -buildx did, and a Make
+// target building the project's image.
+func createDockerImage(dir string, opts options) error {
+	if !opts.buildx {
+		if err := createDockerfile(dir, opts); err != nil {
+			return err
+		}
+	}
+
+	return appendTargets(dir, opts.taskRunner(), DockerMakefileTemplate, newProjectContext(dir, opts))
+}
+
+// createDockerfile writes a Dockerfile building the project's main package
+// and its .dockerignore.
+func createDockerfile(dir string, opts options) error {
+	if err := renderFile(dir, Dockerfile, templatesFS, DockerfileTemplate, newProjectContext(dir, opts)); err != nil {
+		return fmt.Errorf("error creating %s: %w", Dockerfile, err)
+	}
+
+	return createFile(dir, DockerignoreFile, templatesFS, DockerignoreTemplate)
+}
+
+func createRegistryPublishing(dir string, opts options) error {
+	if err := appendFile(dir, GoreleaserFile, templatesFS, registryTemplates[opts.registry]); err != nil {
+		return fmt.Errorf("error updating %s: %w", GoreleaserFile, err)
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, PublishMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	if !opts.buildx {
+		return nil
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, DockerPublishMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func createDebianPackaging(dir string, info packageInfo) error {
+	filesToRender := []templateFile{
+		{DebianControlFile, DebianControlTemplate},
+		{DebianRulesFile, DebianRulesTemplate},
+		{DebianChangelogFile, DebianChangelogTemplate},
+	}
+
+	for _, file := range filesToRender {
+		if err := ensureDir(filepath.Join(dir, filepath.Dir(file.Name))); err != nil {
+			return err
+		}
+
+		if err := renderFile(dir, file.Name, templatesFS, file.Template, info); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	if err := makeExecutable(dir, DebianRulesFile); err != nil {
+		return fmt.Errorf("error making %s executable: %w", DebianRulesFile, err)
+	}
+
+	if err := createFiles(dir, []templateFile{{DebianFormatFile, DebianFormatTemplate}}); err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, DebianMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func createRPMSpec(dir string, info packageInfo) error {
+	spec := info.Package + ".spec"
+	if err := renderFile(dir, spec, templatesFS, RPMSpecTemplate, info); err != nil {
+		return fmt.Errorf("error creating %s: %w", spec, err)
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, RPMMakefileTemplate, info); err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GitignoreFile, templatesFS, RPMGitignoreTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GitignoreFile, err)
+	}
+
+	return nil
+}
+
+// createChocolateyPackage enables the goreleaser chocolateys section, which
+// packs the Windows zip archive, so Windows archives are switched to zip.
+func createChocolateyPackage(dir string) error {
+	bytes, err := os.ReadFile(filepath.Join(dir, GoreleaserFile))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", GoreleaserFile, err)
+	}
+
+	config := strings.Replace(string(bytes), "- format: binary\n",
+		"- format: binary\n  format_overrides:\n    - goos: windows\n      format: zip\n", 1)
+
+	if err := os.WriteFile(filepath.Join(dir, GoreleaserFile), []byte(config), 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", GoreleaserFile, err)
+	}
+
+	if err := appendFile(dir, GoreleaserFile, templatesFS, ChocolateyGoreleaserTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GoreleaserFile, err)
+	}
+
+	return nil
+}
+
+type mocksData struct {
+	ModulePath string
+	Tool       string
+}
+
+func createMocks(dir, tool, modulePath string) error {
+	data := mocksData{ModulePath: modulePath, Tool: tool}
+
+	if err := ensureDir(filepath.Join(dir, filepath.Dir(SenderMockFile))); err != nil {
+		return err
+	}
+
+	filesToRender := []templateFile{
+		{NotifyFile, NotifyTemplate},
+		{NotifyTestFile, NotifyTestTemplate},
+	}
+
+	mock := MockgenSenderTemplate
+	if tool == MocksMockery {
+		mock = MockerySenderTemplate
+		filesToRender = append(filesToRender, templateFile{MockeryConfigFile, MockeryConfigTemplate})
+	}
+
+	for _, file := range filesToRender {
+		if err := renderFile(dir, file.Name, templatesFS, file.Template, data); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	if err := createFile(dir, SenderMockFile, templatesFS, mock); err != nil {
+		return fmt.Errorf("error creating %s: %w", SenderMockFile, err)
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, GenerateMakefileTemplate, data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type diData struct {
+	ModulePath string
+}
+
+// createDependencyInjection generates a server whose config, logger and
+// database are provided by wire or fx.
+func createDependencyInjection(dir, framework, modulePath string) error {
+	data := diData{ModulePath: modulePath}
+
+	if err := createFiles(dir, []templateFile{{ConfigFile, ConfigTemplate}}); err != nil {
+		return err
+	}
+
+	filesToRender := []templateFile{
+		{ServerConfigFile, ServerConfigTemplate},
+		{ServerFile, ServerTemplate},
+		{LoggerFile, LoggerTemplate},
+	}
+
+	if framework == DIWire {
+		filesToRender = append(filesToRender,
+			templateFile{WireProvidersFile, WireProvidersTemplate},
+			templateFile{WireInjectorFile, WireInjectorTemplate},
+			templateFile{WireGenFile, WireGenTemplate},
+			templateFile{MainFile, WireMainTemplate},
+		)
+	} else {
+		filesToRender = append(filesToRender,
+			templateFile{FxModuleFile, FxModuleTemplate},
+			templateFile{MainFile, FxMainTemplate},
+		)
+	}
+
+	if err := renderFiles(dir, filesToRender, data); err != nil {
+		return err
+	}
+
+	if framework == DIWire {
+		if err := appendTargets(dir, TaskRunnerMake, WireMakefileTemplate, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createScripts(dir string) error {
+	if err := ensureDir(filepath.Join(dir, ScriptsDir)); err != nil {
+		return err
+	}
+
+	filesToCreate := []templateFile{
+		{SetupScriptFile, SetupScriptTemplate},
+		{CIBuildScriptFile, CIBuildScriptTemplate},
+	}
+
+	for _, file := range filesToCreate {
+		if err := createExecutableFile(dir, file.Name, templatesFS, file.Template); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	// PowerShell equivalents for Windows, where the scripts above need Git
+	// Bash.
+	return createFiles(dir, []templateFile{
+		{SetupPowerShellFile, SetupPowerShellTemplate},
+		{CIBuildPowerShellFile, CIBuildPowerShellTemplate},
+	})
+}
+
+func createExecutableFile(dir, name string, fsys fs.FS, filePath string) error {
+	if err := createFile(dir, name, fsys, filePath); err != nil {
+		return err
+	}
+
+	return makeExecutable(dir, name)
+}
+
+// makeExecutable sets the executable bit of name. Windows has no such bit,
+// so there it is set on the file in the git index instead, for the file to
+// be executable once checked out elsewhere.
+func makeExecutable(dir, name string) error {
+	if runtime.GOOS == "windows" {
+		// Without a repository, as with -no-git, there is no index to
+		// record the mode in.
+		if !exists(filepath.Join(dir, ".git")) {
+			return nil
+		}
+
+		if err := runCommand(dir, "git", "add", "--chmod=+x", name); err != nil {
+			return fmt.Errorf("error making %s executable in git: %w", name, err)
+		}
+
+		return nil
+	}
+
+	if err := os.Chmod(filepath.Join(dir, name), 0o700); err != nil {
+		return fmt.Errorf("error making %s executable: %w", name, err)
+	}
+
+	return nil
+}
+
+func absPath(name string) (string, error) {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", name, err)
+	}
+
+	return path, nil
+}
+
+func ensureDir(name string) error {
+	if err := os.MkdirAll(name, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating folder: %w", err)
+	}
+
+	return nil
+}
+
+func createFiles(dir string, files []templateFile) error {
+	for _, file := range files {
+		if err := ensureDir(filepath.Join(dir, filepath.Dir(file.Name))); err != nil {
+			return err
+		}
+
+		if err := createFile(dir, file.Name, templatesFS, file.Template); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func createRateLimitMiddleware(dir string) error {
+	return createFiles(dir, []templateFile{
+		{ConfigFile, ConfigTemplate},
+		{RateLimitConfigFile, RateLimitConfigTemplate},
+		{RateLimitConfigTestFile, RateLimitConfigTestTemplate},
+		{RateLimitFile, RateLimitTemplate},
+		{RateLimitTestFile, RateLimitTestTemplate},
+	})
+}
+
+func createCORSMiddleware(dir string) error {
+	return createFiles(dir, []templateFile{
+		{ConfigFile, ConfigTemplate},
+		{CORSConfigFile, CORSConfigTemplate},
+		{CORSConfigTestFile, CORSConfigTestTemplate},
+		{CORSFile, CORSTemplate},
+		{CORSTestFile, CORSTestTemplate},
+	})
+}
+
+func createEnvironmentConfigs(dir string) error {
+	return createFiles(dir, []templateFile{
+		{ConfigFile, ConfigTemplate},
+		{EnvironmentConfigFile, EnvironmentConfigTemplate},
+		{EnvironmentConfigTestFile, EnvironmentConfigTestTemplate},
+		{BaseConfigFile, BaseConfigTemplate},
+		{DevConfigFile, DevConfigTemplate},
+		{StagingConfigFile, StagingConfigTemplate},
+		{ProdConfigFile, ProdConfigTemplate},
+	})
+}
+
+func createWebAssets(dir string) error {
+	err := createFiles(dir, []templateFile{
+		{WebEmbedFile, WebEmbedTemplate},
+		{WebHandlerFile, WebHandlerTemplate},
+		{WebHandlerTestFile, WebHandlerTestTemplate},
+		{WebIndexFile, WebIndexTemplate},
+		{WebAppFile, WebAppTemplate},
+		{WebSourceFile, WebSourceTemplate},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := createExecutableFile(dir, AssetsScriptFile, templatesFS, AssetsScriptTemplate); err != nil {
+		return fmt.Errorf("error creating %s: %w", AssetsScriptFile, err)
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, AssetsMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GitignoreFile, templatesFS, AssetsGitignoreTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GitignoreFile, err)
+	}
+
+	return nil
+}
+
+func createLocales(dir string) error {
+	err := createFiles(dir, []templateFile{
+		{LocaleFile, LocaleTemplate},
+		{LocaleTestFile, LocaleTestTemplate},
+		{LocaleEnFile, LocaleEnTemplate},
+		{LocaleSvFile, LocaleSvTemplate},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, I18nMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package goinit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckModulePath(t *testing.T) {
+	tests := []struct {
+		path string
+		err  string
+	}{
+		{"example.com/team/service", ""},
+		{"github.com/octo/my-service", ""},
+		{"service", ""},
+		{"example.com/team/v2", ""},
+		{"", "module path is empty"},
+		{"/service", "empty path element"},
+		{"example.com/team/", "empty path element"},
+		{"example.com//service", "empty path element"},
+		{"example.com/.service", "starts with a dot"},
+		{"example.com/service.", "ends with a dot"},
+		{"example.com/my service", `contains ' '`},
+		{"example.com/con", "reserved file name on Windows"},
+		{"Example.com/service", "has to be lowercase"},
+		{"-example.com/service", "cannot start with a dash"},
+	}
+
+	for _, tt := range tests {
+		err := checkModulePath(tt.path)
+
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("checkModulePath(%q) = %v, want nil", tt.path, err)
+		case tt.err != "" && err == nil:
+			t.Errorf("checkModulePath(%q) = nil, want an error containing %q", tt.path, tt.err)
+		case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+			t.Errorf("checkModulePath(%q) = %v, want an error containing %q", tt.path, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package goinit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ManifestFile   = ".goinit.yaml"
+	ManifestHeader = "# Written by goinit, which reads it back to diff, undo and upgrade the project.\n"
+)
+
+// projectManifest records how a project was generated. It is written as a
+// small subset of YAML, with every value double quoted, so goinit can read
+// it back without a YAML library.
+type projectManifest struct {
+	Version string
+	Name    string
+	// Conventions is the number of migrations the project is up to date
+	// with.
+	Conventions int
+	// CreatedRepository is set when goinit initialized the git repository.
+	CreatedRepository bool
+	// Module and GoVersion are the module path and Go version the project
+	// was generated with, which diff and upgrade keep even when they would
+	// now be derived differently.
+	Module    string
+	GoVersion string
+	// Template is the -template source the project was generated from,
+	// and TemplateRevision the commit of its repository.
+	Template         string
+	TemplateRevision string
+	// Options are the command line arguments the project was generated
+	// with, other than the project name.
+	Options []string
+	// GitConfig are the repository configuration keys goinit set.
+	GitConfig []string
+	// Files maps the files goinit created to their SHA-256 checksum.
+	Files map[string]string
+}
+
+// runFlags change how goinit runs rather than what it generates, so they
+// are not recorded.
+var runFlags = map[string]bool{
+	NoColorFlag:        true,
+	TimeoutFlag:        true,
+	NetworkTimeoutFlag: true,
+	AnswersFlag:        true,
+	InteractiveFlag:    true,
+	DryRunFlag:         true,
+	KeepPartialFlag:    true,
+	HereFlag:           true,
+	ForceFlag:          true,
+	SkipExistingFlag:   true,
+	TemplateFlag:       true,
+	VerboseFlag:        true,
+	QuietFlag:          true,
+	JSONFlag:           true,
+}
+
+// changedArgs returns the flags of fs set to other than their defaults as
+// command line arguments, leaving out the project name and output flags.
+func changedArgs(fs *flag.FlagSet) []string {
+	var args []string
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name != ProjectNameFlag && !runFlags[f.Name] && f.Value.String() != f.DefValue {
+			args = append(args, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+
+	return args
+}
+
+// checksumFiles returns the checksums of the files under dir, leaving out
+// the repository and the manifest.
+func checksumFiles(dir string) (map[string]string, error) {
+	names, err := walkFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string, len(names))
+
+	for name := range names {
+		sum, err := fileChecksum(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		files[name] = sum
+	}
+
+	return files, nil
+}
+
+func fileChecksum(name string) (string, error) {
+	data, err := os.ReadFile(filepath.FromSlash(name))
+	if err != nil {
+		return "", err
+	}
+
+	return checksum(data), nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// createManifest records the options with the module path, Go version and
+// templates they resolved to, the files created and the git configuration
+// set, once the project is complete.
+func createManifest(dir string, opts options) error {
+	files, err := checksumFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	var gitConfig []string
+	if !opts.skips(ComponentHooks) {
+		gitConfig = hookManagers[opts.hooks].gitConfig
+	}
+
+	return writeManifest(filepath.Join(dir, ManifestFile), projectManifest{
+		Version:           currentVersion(),
+		Name:              opts.projectName,
+		Conventions:       len(migrations),
+		CreatedRepository: true,
+		Module:            opts.modulePath(),
+		GoVersion:         goModDirective(filepath.Join(dir, "go.mod"), "go"),
+		Template:          templatesSource.source,
+		TemplateRevision:  templatesSource.revision,
+		Options:           opts.args,
+		GitConfig:         gitConfig,
+		Files:             files,
+	})
+}
+
+func writeManifest(name string, m projectManifest) error {
+	var b strings.Builder
+
+	b.WriteString(ManifestHeader)
+	fmt.Fprintf(&b, "version: %s\n", strconv.Quote(m.Version))
+	fmt.Fprintf(&b, "name: %s\n", strconv.Quote(m.Name))
+	fmt.Fprintf(&b, "conventions: %s\n", strconv.Quote(strconv.Itoa(m.Conventions)))
+	fmt.Fprintf(&b, "created_repository: %s\n", strconv.Quote(strconv.FormatBool(m.CreatedRepository)))
+	fmt.Fprintf(&b, "module: %s\n", strconv.Quote(m.Module))
+	fmt.Fprintf(&b, "go_version: %s\n", strconv.Quote(m.GoVersion))
+
+	if m.Template != "" {
+		fmt.Fprintf(&b, "template: %s\n", strconv.Quote(m.Template))
+		fmt.Fprintf(&b, "template_revision: %s\n", strconv.Quote(m.TemplateRevision))
+	}
+
+	writeManifestList(&b, "options", m.Options)
+	writeManifestList(&b, "git_config", m.GitConfig)
+
+	paths := make([]string, 0, len(m.Files))
+	for path := range m.Files {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	b.WriteString("files:")
+
+	if len(paths) == 0 {
+		b.WriteString(" {}")
+	}
+
+	b.WriteString("\n")
+
+	for _, path := range paths {
+		fmt.Fprintf(&b, "  %s: %s\n", strconv.Quote(path), strconv.Quote(m.Files[path]))
+	}
+
+	return os.WriteFile(name, []byte(b.String()), 0o644)
+}
+
+func writeManifestList(b *strings.Builder, key string, values []string) {
+	b.WriteString(key + ":")
+
+	if len(values) == 0 {
+		b.WriteString(" []")
+	}
+
+	b.WriteString("\n")
+
+	for _, value := range values {
+		fmt.Fprintf(b, "  - %s\n", strconv.Quote(value))
+	}
+}
+
+func readManifest(name string) (projectManifest, error) {
+	m := projectManifest{Files: make(map[string]string)}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return m, fmt.Errorf("error reading %s, was the project generated by goinit? %w", name, err)
+	}
+	defer f.Close()
+
+	var key string
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "  - ") {
+			value, err := strconv.Unquote(strings.TrimPrefix(text, "  - "))
+			if err != nil {
+				return m, fmt.Errorf("%s:%d: %s item is not a quoted string", name, line, key)
+			}
+
+			switch key {
+			case "options":
+				m.Options = append(m.Options, value)
+			case "git_config":
+				m.GitConfig = append(m.GitConfig, value)
+			default:
+				return m, fmt.Errorf("%s:%d: unexpected list item", name, line)
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(text, "  ") {
+			path, value, err := parseManifestEntry(strings.TrimPrefix(text, "  "))
+			if err != nil || key != "files" {
+				return m, fmt.Errorf("%s:%d: unexpected entry", name, line)
+			}
+
+			m.Files[path] = value
+
+			continue
+		}
+
+		k, raw, ok := strings.Cut(text, ":")
+		if !ok {
+			return m, fmt.Errorf("%s:%d: expected a key", name, line)
+		}
+
+		key, raw = k, strings.TrimSpace(raw)
+		if raw == "" || raw == "[]" || raw == "{}" {
+			continue
+		}
+
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			return m, fmt.Errorf("%s:%d: %s is not a quoted string", name, line, key)
+		}
+
+		switch key {
+		case "version":
+			m.Version = value
+		case "name":
+			m.Name = value
+		case "conventions":
+			if m.Conventions, err = strconv.Atoi(value); err != nil {
+				return m, fmt.Errorf("%s:%d: conventions is not a number", name, line)
+			}
+		case "created_repository":
+			if m.CreatedRepository, err = strconv.ParseBool(value); err != nil {
+				return m, fmt.Errorf("%s:%d: created_repository is not a boolean", name, line)
+			}
+		case "module":
+			m.Module = value
+		case "go_version":
+			m.GoVersion = value
+		case "template":
+			m.Template = value
+		case "template_revision":
+			m.TemplateRevision = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return m, fmt.Errorf("error reading %s: %w", name, err)
+	}
+
+	return m, nil
+}
+
+// parseManifestEntry parses a `"key": "value"` map entry.
+func parseManifestEntry(text string) (string, string, error) {
+	quoted, err := strconv.QuotedPrefix(text)
+	if err != nil {
+		return "", "", err
+	}
+
+	key, _ := strconv.Unquote(quoted)
+
+	rest := strings.TrimPrefix(text[len(quoted):], ": ")
+	if rest == text[len(quoted):] {
+		return "", "", fs.ErrInvalid
+	}
+
+	value, err := strconv.Unquote(rest)
+
+	return key, value, err
+}
+
+// options parses the recorded options back, as they were when the project
+// was generated, with the module path and Go version it was generated with.
+func (m projectManifest) options() (options, error) {
+	var opts options
+
+	set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+	registerFlags(set, &opts)
+
+	if err := set.Parse(append([]string{"-" + ProjectNameFlag + "=" + m.Name}, m.Options...)); err != nil {
+		return opts, fmt.Errorf("error parsing the recorded options: %w", err)
+	}
+
+	if opts.module == "" {
+		opts.module = m.Module
+	}
+
+	opts.goVersion = m.GoVersion
+	opts.args = m.Options
+
+	return opts, opts.validate()
+}
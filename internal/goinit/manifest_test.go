@@ -0,0 +1,92 @@
+package goinit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ManifestFile)
+
+	want := projectManifest{
+		Version:           "v1.2.3",
+		Name:              "payments",
+		Conventions:       3,
+		CreatedRepository: true,
+		Module:            "example.com/team/payments",
+		GoVersion:         "1.22",
+		Template:          "https://example.com/templates.git",
+		TemplateRevision:  "0123456789abcdef",
+		Options:           []string{"-type=api", `-set=greeting="hi: there"`},
+		GitConfig:         []string{"core.hooksPath"},
+		Files: map[string]string{
+			"go.mod":         checksum([]byte("module example.com/team/payments\n")),
+			"cmd/my app.go":  checksum(nil),
+			`quoted"name.md`: checksum([]byte("#\n")),
+		},
+	}
+
+	if err := writeManifest(name, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readManifest(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readManifest = %+v, want %+v", got, want)
+	}
+}
+
+func TestManifestEmptyLists(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ManifestFile)
+
+	if err := writeManifest(name, projectManifest{Name: "payments"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readManifest(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Name != "payments" || got.Template != "" || len(got.Options) != 0 || len(got.GitConfig) != 0 || len(got.Files) != 0 {
+		t.Errorf("readManifest = %+v, want only the name", got)
+	}
+}
+
+func TestReadManifestErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		err     string
+	}{
+		{"name: payments\n", "name is not a quoted string"},
+		{"conventions: \"three\"\n", "conventions is not a number"},
+		{"created_repository: \"maybe\"\n", "created_repository is not a boolean"},
+		{"options:\n  - -type=api\n", "options item is not a quoted string"},
+		{"name: \"payments\"\n  - \"-type=api\"\n", "unexpected list item"},
+		{"options: []\n  \"go.mod\": \"abc\"\n", "unexpected entry"},
+		{"name\n", "expected a key"},
+	}
+
+	for _, tt := range tests {
+		name := filepath.Join(t.TempDir(), ManifestFile)
+		if err := os.WriteFile(name, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := readManifest(name)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("readManifest(%q) = %v, want an error containing %q", tt.content, err, tt.err)
+		}
+	}
+
+	if _, err := readManifest(filepath.Join(t.TempDir(), ManifestFile)); err == nil || !strings.Contains(err.Error(), "was the project generated by goinit") {
+		t.Errorf("readManifest of a missing file = %v, want an error asking whether goinit generated the project", err)
+	}
+}
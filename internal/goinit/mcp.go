@@ -0,0 +1,81 @@
+package goinit
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MCPMainTemplate        = "templates/mcp/main.go.tmpl"
+	MCPServerTemplate      = "templates/mcp/server.go.tmpl"
+	MCPServerTestTemplate  = "templates/mcp/server_internal_test.go.tmpl"
+	MCPServerJSONTemplate  = "templates/mcp/server.json.tmpl"
+	MCPDocsTemplate        = "templates/mcp/mcp.md.tmpl"
+	MCPDockerLabelTemplate = "templates/mcp/label.Dockerfile.tmpl"
+	MCPMakefileTemplate    = "templates/mcp/mcp.mk"
+	MCPServerFile          = "internal/mcpserver/server.go"
+	MCPServerTestFile      = "internal/mcpserver/server_internal_test.go"
+	MCPServerJSONFile      = "server.json"
+	MCPDocsFile            = "docs/mcp.md"
+)
+
+// mcpData is what the MCP server templates are rendered with.
+type mcpData struct {
+	packageInfo
+	// ServerName is the server's name in the MCP registry, whose namespace
+	// has to be proven: io.github.<owner> through a GitHub login.
+	ServerName string
+	Image      string
+}
+
+func newMCPData(dir string, opts options) mcpData {
+	info := newPackageInfo(dir, opts)
+	data := mcpData{
+		packageInfo: info,
+		ServerName:  "com.example/" + info.Package,
+		Image:       "ghcr.io/example/" + info.Package,
+	}
+
+	if repo, err := githubRepo(info.ModulePath); err == nil {
+		repo = strings.ToLower(repo)
+		data.ServerName = "io.github." + repo
+		data.Image = "ghcr.io/" + repo
+	}
+
+	return data
+}
+
+// createMCPLayout generates a Model Context Protocol server with an
+// example tool and resource served over stdio or SSE, and the Dockerfile
+// and server.json publishing it to the MCP registry.
+func createMCPLayout(dir string, opts options) error {
+	data := newMCPData(dir, opts)
+
+	err := renderFiles(dir, []templateFile{
+		{MainFile, MCPMainTemplate},
+		{MCPServerFile, MCPServerTemplate},
+		{MCPServerTestFile, MCPServerTestTemplate},
+		{MCPServerJSONFile, MCPServerJSONTemplate},
+		{MCPDocsFile, MCPDocsTemplate},
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	// -buildx or -docker already created the Dockerfile.
+	if !opts.hasDockerfile() {
+		if err := createDockerfile(dir, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := appendRenderedFile(dir, Dockerfile, templatesFS, MCPDockerLabelTemplate, data); err != nil {
+		return fmt.Errorf("error updating %s: %w", Dockerfile, err)
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, MCPMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
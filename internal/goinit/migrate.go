@@ -0,0 +1,186 @@
+package goinit
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	LegacyGolintciFile     = ".golintci.yml"
+	LegacyPreCommitScript  = "scripts/pre-commit"
+	LegacyPreCommitHook    = ".git/hooks/pre-commit"
+	LegacyHookInstallation = "cp scripts/pre-commit .git/hooks/pre-commit\nchmod +x .git/hooks/pre-commit"
+	HookInstallation       = "git config core.hooksPath " + GitHooksDir
+)
+
+// migration upgrades a project generated by an older goinit to one of the
+// conventions it generates today. Migrations run in order and each checks
+// whether it applies, so running them again changes nothing.
+type migration struct {
+	Description string
+	// Pending reports whether the project still needs the migration.
+	Pending func() bool
+	Apply   func() error
+}
+
+// migrations are numbered by their position: a project at conventions n
+// has had the first n applied. Append new ones, never reorder them.
+var migrations = []migration{
+	{
+		Description: "rename " + LegacyGolintciFile + " to " + GolangciFile + ", the name golangci-lint reads",
+		Pending:     func() bool { return exists(LegacyGolintciFile) && !exists(GolangciFile) },
+		Apply:       func() error { return os.Rename(LegacyGolintciFile, GolangciFile) },
+	},
+	{
+		Description: "move the pre-commit hook to " + GitHooksDir + " and run it through core.hooksPath",
+		Pending:     func() bool { return !exists(PreCommitHookFile) || gitConfig("core.hooksPath") != GitHooksDir },
+		Apply:       moveHooks,
+	},
+}
+
+// migrate applies the migrations the project in the working directory is
+// missing, or with -dry-run lists them.
+func migrate(args []string) error {
+	set := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := set.Bool("dry-run", false, "list the pending migrations without applying them")
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	if !exists(".git") {
+		return errors.New("run goinit migrate from the root of the project's repository")
+	}
+
+	// Projects generated before the manifest existed have none, their
+	// migrations are found from the files alone.
+	manifest, err := readManifest(ManifestFile)
+	hasManifest := err == nil
+
+	applied := 0
+
+	for i, m := range migrations {
+		if i < manifest.Conventions || !m.Pending() {
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("Pending: %s\n", m.Description)
+			continue
+		}
+
+		if err := m.Apply(); err != nil {
+			return fmt.Errorf("error applying migration %d (%s): %w", i+1, m.Description, err)
+		}
+
+		fmt.Printf("Applied: %s\n", m.Description)
+		applied++
+	}
+
+	if *dryRun {
+		return nil
+	}
+
+	if applied == 0 {
+		fmt.Println("The project follows the current conventions")
+	}
+
+	if hasManifest {
+		manifest.Conventions = len(migrations)
+		if err := writeManifest(ManifestFile, manifest); err != nil {
+			return fmt.Errorf("error updating %s: %w", ManifestFile, err)
+		}
+	}
+
+	return nil
+}
+
+// moveHooks moves the hook out of scripts/, keeping any local changes to
+// it (or restores it when missing), points core.hooksPath at it and switches setup.sh to configuring git
+// instead of copying the hook.
+func moveHooks() error {
+	hook, err := os.ReadFile(PreCommitHookFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		hook, err = os.ReadFile(LegacyPreCommitScript)
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		hook, err = fs.ReadFile(templatesFS, PreCommitHookTemplate)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := ensureDir(filepath.Dir(PreCommitHookFile)); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(PreCommitHookFile, hook, 0o755); err != nil {
+		return err
+	}
+
+	if err := makeExecutable(".", PreCommitHookFile); err != nil {
+		return err
+	}
+
+	if err := runCommand("", "git", "config", "core.hooksPath", GitHooksDir); err != nil {
+		return fmt.Errorf("error setting core.hooksPath: %w", err)
+	}
+
+	if err := os.Remove(LegacyPreCommitScript); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// core.hooksPath makes git ignore .git/hooks, so an installed copy of
+	// the hook is dead. A hook that differs was written by someone else.
+	if installed, err := os.ReadFile(LegacyPreCommitHook); err == nil {
+		if bytes.Equal(installed, hook) {
+			if err := os.Remove(LegacyPreCommitHook); err != nil {
+				return err
+			}
+		} else {
+			log.Printf("%s differs from the moved hook and no longer runs, merge it into %s", LegacyPreCommitHook, PreCommitHookFile)
+		}
+	}
+
+	setup, err := os.ReadFile(SetupScriptFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	updated := strings.Replace(string(setup), LegacyHookInstallation, HookInstallation, 1)
+	if updated == string(setup) {
+		log.Printf("Could not update %s, replace its hook installation with `%s`", SetupScriptFile, HookInstallation)
+		return nil
+	}
+
+	return os.WriteFile(SetupScriptFile, []byte(updated), 0o755)
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// gitConfig returns the value of a git configuration key, or "" if it is
+// not set.
+func gitConfig(key string) string {
+	out, err := commandOutput("", "git", "config", "--get", key)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,65 @@
+package goinit
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MobileMainTemplate      = "templates/mobile/main.go.tmpl"
+	MobilePackageTemplate   = "templates/mobile/mobile.go.tmpl"
+	MobileTestTemplate      = "templates/mobile/mobile_internal_test.go.tmpl"
+	MobileBindTemplate      = "templates/mobile/bind.go.tmpl"
+	MobileMakefileTemplate  = "templates/mobile/mobile.mk.tmpl"
+	MobileWorkflowTemplate  = "templates/mobile/mobile.yml"
+	MobileGitignoreTemplate = "templates/mobile/mobile.gitignore"
+	MobilePackageFile       = "mobile/mobile.go"
+	MobileTestFile          = "mobile/mobile_internal_test.go"
+	MobileBindFile          = "mobile/bind.go"
+	MobileWorkflowFile      = ".github/workflows/mobile.yml"
+)
+
+// mobileData is what the mobile templates are rendered with.
+type mobileData struct {
+	packageInfo
+	// JavaPackage is the Java package of the generated Android classes.
+	JavaPackage string
+}
+
+// createMobileLayout generates a package bindable with gomobile, Make
+// targets building it into an Android AAR and an iOS XCFramework and a
+// workflow building both.
+func createMobileLayout(dir string, opts options) error {
+	info := newPackageInfo(dir, opts)
+	data := mobileData{
+		packageInfo: info,
+		JavaPackage: "com.example." + strings.ReplaceAll(info.Package, "-", ""),
+	}
+
+	files := []templateFile{
+		{MainFile, MobileMainTemplate},
+		{MobilePackageFile, MobilePackageTemplate},
+		{MobileTestFile, MobileTestTemplate},
+	}
+
+	if err := renderFiles(dir, files, data); err != nil {
+		return err
+	}
+
+	if err := createFiles(dir, []templateFile{
+		{MobileBindFile, MobileBindTemplate},
+		{MobileWorkflowFile, MobileWorkflowTemplate},
+	}); err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, MobileMakefileTemplate, data); err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GitignoreFile, templatesFS, MobileGitignoreTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GitignoreFile, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package goinit
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+var (
+	windowsReservedNames = regexp.MustCompile(`(?i)^(con|prn|aux|nul|com[1-9]|lpt[1-9])$`)
+	invalidNameChars     = regexp.MustCompile(`[^a-z0-9._~-]+`)
+)
+
+// pathElementProblem returns why elem cannot be an element of a module
+// path, or "" when it can.
+func pathElementProblem(elem string) string {
+	switch {
+	case strings.HasPrefix(elem, "."):
+		return "starts with a dot"
+	case strings.HasSuffix(elem, "."):
+		return "ends with a dot"
+	case windowsReservedNames.MatchString(strings.SplitN(elem, ".", 2)[0]):
+		return "is a reserved file name on Windows"
+	}
+
+	for _, r := range elem {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || strings.ContainsRune("-._~", r)) {
+			return fmt.Sprintf("contains %q", r)
+		}
+	}
+
+	return ""
+}
+
+// checkProjectName checks that name works as the project's directory and
+// as the last element of the module path derived from it, and suggests a
+// name that does when it does not.
+func checkProjectName(name string) error {
+	if name == "" {
+		return errors.New("project name is empty")
+	}
+
+	var problem string
+
+	switch {
+	case strings.ContainsAny(name, `/\`):
+		problem = "names the project's directory, which cannot have slashes"
+	case strings.ContainsAny(name, " \t"):
+		problem = "contains spaces"
+	case strings.HasPrefix(name, "-"):
+		problem = "starts with a dash"
+	default:
+		problem = pathElementProblem(name)
+	}
+
+	if problem == "" {
+		return nil
+	}
+
+	return fmt.Errorf("invalid project name %q: %s, use %q", name, problem, sanitizeProjectName(name))
+}
+
+// sanitizeProjectName turns name into a valid project name: lowercase
+// letters, digits and "-._~", with dashes in place of the rest.
+func sanitizeProjectName(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-._~")
+
+	switch {
+	case name == "":
+		return DefaultProjectName
+	case windowsReservedNames.MatchString(strings.SplitN(name, ".", 2)[0]):
+		return "go-" + name
+	}
+
+	return name
+}
+
+// warnUppercase warns about a project name with uppercase letters in the
+// derived module path, which is valid but has to be imported with the same
+// case, and names directories that differ only in case on macOS and Windows.
+func warnUppercase(opts options) {
+	if opts.module == "" && opts.projectName != strings.ToLower(opts.projectName) {
+		log.Printf("The module path %s has uppercase letters, which its imports have to match, consider %q as the name", opts.modulePath(), sanitizeProjectName(opts.projectName))
+	}
+}
@@ -0,0 +1,70 @@
+package goinit
+
+const (
+	OperatorGroupVersionTemplate         = "templates/operator/groupversion_info.go.tmpl"
+	OperatorTypesTemplate                = "templates/operator/example_types.go.tmpl"
+	OperatorDeepCopyTemplate             = "templates/operator/zz_generated.deepcopy.go.tmpl"
+	OperatorControllerTemplate           = "templates/operator/example_controller.go.tmpl"
+	OperatorControllerTestTemplate       = "templates/operator/example_controller_internal_test.go.tmpl"
+	OperatorMainTemplate                 = "templates/operator/main.go.tmpl"
+	OperatorCRDTemplate                  = "templates/operator/config/crd.yaml.tmpl"
+	OperatorCRDKustomizationTemplate     = "templates/operator/config/crd-kustomization.yaml.tmpl"
+	OperatorRoleTemplate                 = "templates/operator/config/role.yaml.tmpl"
+	OperatorRoleBindingTemplate          = "templates/operator/config/role_binding.yaml"
+	OperatorLeaderElectionTemplate       = "templates/operator/config/leader_election_role.yaml"
+	OperatorServiceAccountTemplate       = "templates/operator/config/service_account.yaml"
+	OperatorRBACKustomizationTemplate    = "templates/operator/config/rbac-kustomization.yaml"
+	OperatorManagerTemplate              = "templates/operator/config/manager.yaml"
+	OperatorManagerKustomizationTemplate = "templates/operator/config/manager-kustomization.yaml"
+	OperatorDefaultKustomizationTemplate = "templates/operator/config/default-kustomization.yaml.tmpl"
+	OperatorSampleTemplate               = "templates/operator/config/sample.yaml.tmpl"
+	OperatorMakefileTemplate             = "templates/operator/operator.mk"
+	OperatorAPIDir                       = "api/v1alpha1/"
+	OperatorConfigDir                    = "config/"
+	OperatorControllerFile               = "internal/controller/example_controller.go"
+	OperatorControllerTestFile           = "internal/controller/example_controller_internal_test.go"
+)
+
+// operatorData is what the operator templates are rendered with. Group is
+// the API group of the example resource, like kubebuilder's --domain it
+// should be changed to a domain the project owns.
+type operatorData struct {
+	packageInfo
+	Group string
+}
+
+// createOperatorLayout generates a kubebuilder style operator: an API
+// types package, a controller and the kustomize manifests deploying them.
+func createOperatorLayout(dir string, opts options) error {
+	info := newPackageInfo(dir, opts)
+	data := operatorData{packageInfo: info, Group: info.Package + ".example.com"}
+
+	err := renderFiles(dir, []templateFile{
+		{OperatorAPIDir + "groupversion_info.go", OperatorGroupVersionTemplate},
+		{OperatorAPIDir + "example_types.go", OperatorTypesTemplate},
+		{OperatorAPIDir + "zz_generated.deepcopy.go", OperatorDeepCopyTemplate},
+		{OperatorControllerFile, OperatorControllerTemplate},
+		{OperatorControllerTestFile, OperatorControllerTestTemplate},
+		{MainFile, OperatorMainTemplate},
+		{OperatorConfigDir + "crd/bases/" + data.Group + "_examples.yaml", OperatorCRDTemplate},
+		{OperatorConfigDir + "crd/kustomization.yaml", OperatorCRDKustomizationTemplate},
+		{OperatorConfigDir + "rbac/role.yaml", OperatorRoleTemplate},
+		{OperatorConfigDir + "rbac/role_binding.yaml", OperatorRoleBindingTemplate},
+		{OperatorConfigDir + "rbac/leader_election_role.yaml", OperatorLeaderElectionTemplate},
+		{OperatorConfigDir + "rbac/service_account.yaml", OperatorServiceAccountTemplate},
+		{OperatorConfigDir + "rbac/kustomization.yaml", OperatorRBACKustomizationTemplate},
+		{OperatorConfigDir + "manager/manager.yaml", OperatorManagerTemplate},
+		{OperatorConfigDir + "manager/kustomization.yaml", OperatorManagerKustomizationTemplate},
+		{OperatorConfigDir + "default/kustomization.yaml", OperatorDefaultKustomizationTemplate},
+		{OperatorConfigDir + "samples/v1alpha1_example.yaml", OperatorSampleTemplate},
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, OperatorMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package goinit
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	VerboseFlag = "v"
+	QuietFlag   = "q"
+	JSONFlag    = "json"
+)
+
+// logs reports what goinit does beyond the steps: the warnings and errors,
+// and with -v the commands it runs and the files it writes. With -json
+// each of them, and each step, is a JSON object on a line of stdout.
+var logs = &logger{}
+
+type logger struct {
+	mu      sync.Mutex
+	verbose bool
+	quiet   bool
+	enc     *json.Encoder
+}
+
+// logEvent is a line of the -json output. Event is step, command, file,
+// warning or error.
+type logEvent struct {
+	Event   string `json:"event"`
+	Message string `json:"message,omitempty"`
+	Command string `json:"command,omitempty"`
+	Path    string `json:"path,omitempty"`
+}
+
+// outputFlags defines -v, -q and -json on fs. The returned function points
+// the steps and the log at the output they select once fs is parsed.
+func outputFlags(fs *flag.FlagSet) func(noColor bool) error {
+	verbose := fs.Bool(VerboseFlag, false, "print each command run and file written")
+	quiet := fs.Bool(QuietFlag, false, "print errors only")
+	jsonOutput := fs.Bool(JSONFlag, false, "print the steps, commands, files, warnings and errors as JSON lines on stdout")
+
+	return func(noColor bool) error {
+		if *verbose && *quiet {
+			return errors.New("-v and -q cannot be combined")
+		}
+
+		logs.mu.Lock()
+		logs.verbose, logs.quiet = *verbose, *quiet
+
+		if *jsonOutput {
+			logs.enc = json.NewEncoder(os.Stdout)
+		}
+		logs.mu.Unlock()
+
+		switch {
+		case *jsonOutput:
+			steps = newProgress(io.Discard, false, false)
+			log.SetFlags(0)
+			log.SetOutput(warningWriter{})
+		case *quiet:
+			steps = newProgress(io.Discard, false, false)
+			log.SetOutput(io.Discard)
+		default:
+			steps = newTerminalProgress(noColor)
+			log.SetOutput(steps)
+		}
+
+		return nil
+	}
+}
+
+func (l *logger) emit(e logEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.enc != nil {
+		l.enc.Encode(e)
+	}
+}
+
+func (l *logger) isJSON() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.enc != nil
+}
+
+func (l *logger) isQuiet() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.quiet
+}
+
+func (l *logger) isVerbose() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.verbose
+}
+
+// step reports the start of a generation step in JSON, the terminal shows
+// it as progress.
+func (l *logger) step(step string) {
+	l.emit(logEvent{Event: "step", Message: step})
+}
+
+// command reports a command about to run.
+func (l *logger) command(name string, arg []string) {
+	line := strings.Join(append([]string{name}, arg...), " ")
+
+	switch {
+	case l.isJSON():
+		l.emit(logEvent{Event: "command", Command: line})
+	case l.isVerbose():
+		log.Printf("Running %s", line)
+	}
+}
+
+// file reports a file written.
+func (l *logger) file(name string) {
+	switch {
+	case l.isJSON():
+		l.emit(logEvent{Event: "file", Path: name})
+	case l.isVerbose():
+		log.Printf("Wrote %s", name)
+	}
+}
+
+// summary prints what generating opts in dir created and the next steps,
+// which -q and -json leave out.
+func (l *logger) summary(dir string, opts options) {
+	if l.isQuiet() || l.isJSON() {
+		return
+	}
+
+	if err := printSummary(os.Stdout, dir, opts); err != nil {
+		log.Printf("Could not list the project's files: %v", err)
+	}
+}
+
+// ReportError prints the error Run returned, which -q does not silence, as
+// an error event with -json.
+func ReportError(err error) {
+	logs.error(err)
+}
+
+func (l *logger) error(err error) {
+	message := strings.TrimSuffix(err.Error(), "\n")
+
+	if l.isJSON() {
+		l.emit(logEvent{Event: "error", Message: message})
+	} else {
+		log.New(os.Stderr, "", log.LstdFlags).Print(message)
+	}
+}
+
+// warningWriter turns the log output into warning events.
+type warningWriter struct{}
+
+func (warningWriter) Write(b []byte) (int, error) {
+	logs.emit(logEvent{Event: "warning", Message: strings.TrimSuffix(string(b), "\n")})
+	return len(b), nil
+}
@@ -0,0 +1,258 @@
+package goinit
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	SSHConfigDir    = ".ssh"
+	SSHConfigFile   = "config"
+	DefaultAlias    = "project/"
+	HostFlag        = "host"
+	GithubDomain    = "github.com"
+	GitlabDomain    = "gitlab.com"
+	BitbucketDomain = "bitbucket.org"
+	ForgeGithub     = "github"
+	ForgeGitlab     = "gitlab"
+	ForgeBitbucket  = "bitbucket"
+	// SSHGitUser is the user every forge's SSH remotes log in as, which is
+	// not the account's.
+	SSHGitUser = "git"
+)
+
+// forgeCIProviders maps the forges to the CI provider generated for the
+// projects they host.
+var forgeCIProviders = map[string]string{
+	ForgeGithub:    CIGithub,
+	ForgeGitlab:    CIGitlab,
+	ForgeBitbucket: CIBitbucket,
+}
+
+// userSource is a place the user of a forge is looked up.
+type userSource struct {
+	name string
+	user func() string
+}
+
+type detectedPrefix struct {
+	prefix string
+	source string
+}
+
+// detectedPrefixes caches the module path prefix of each host, which is
+// looked up once as it runs git and gh.
+var detectedPrefixes = struct {
+	sync.Mutex
+	hosts map[string]detectedPrefix
+}{hosts: make(map[string]detectedPrefix)}
+
+// forgeName returns the forge of host: github, gitlab for gitlab.com and
+// the self-hosted gitlab.* hosts, bitbucket, or "" for an unknown host.
+func forgeName(host string) string {
+	host = strings.ToLower(host)
+
+	switch {
+	case host == GithubDomain:
+		return ForgeGithub
+	case host == GitlabDomain || strings.HasPrefix(host, ForgeGitlab+"."):
+		return ForgeGitlab
+	case host == BitbucketDomain:
+		return ForgeBitbucket
+	}
+
+	return ""
+}
+
+// forgeHost returns the -host, or the host of the module path, or "" when
+// the module path has none.
+func (o options) forgeHost() string {
+	if o.host != "" {
+		return o.host
+	}
+
+	first, _, _ := strings.Cut(o.modulePath(), "/")
+	if !strings.Contains(first, ".") {
+		return ""
+	}
+
+	return first
+}
+
+func modulePath(host, name string) string {
+	prefix, _ := modulePrefix(host)
+	return prefix + name
+}
+
+// modulePrefix returns the prefix of derived module paths, with a trailing
+// slash, and where it comes from. Without a host it is the config file's
+// module_prefix, or else the host defaults to github.com. It is then the
+// host and the first user userSources find, or project/ without one.
+func modulePrefix(host string) (string, string) {
+	if host == "" {
+		if userConfig.ModulePrefix != "" {
+			return userConfig.ModulePrefix + "/", "config file"
+		}
+
+		host = GithubDomain
+	}
+
+	detectedPrefixes.Lock()
+	defer detectedPrefixes.Unlock()
+
+	p, ok := detectedPrefixes.hosts[host]
+	if !ok {
+		p = detectedPrefix{DefaultAlias, "default"}
+
+		for _, s := range userSources(host) {
+			if user := s.user(); user != "" {
+				p = detectedPrefix{host + "/" + user + "/", s.name}
+				break
+			}
+		}
+
+		detectedPrefixes.hosts[host] = p
+	}
+
+	return p.prefix, p.source
+}
+
+// userSources returns where the user of host is looked up, in order: the
+// forge's user in the global git configuration, which hub and other tools
+// read too, the owner of the origin remote, the account the GitHub CLI is
+// logged in as for github.com, and the SSH config.
+func userSources(host string) []userSource {
+	var sources []userSource
+
+	forge := forgeName(host)
+
+	if forge != "" {
+		key := forge + ".user"
+		sources = append(sources, userSource{"git config " + key, func() string {
+			return commandLine("git", "config", "--global", "--get", key)
+		}})
+	}
+
+	sources = append(sources, userSource{"git remote " + GitRemote, func() string {
+		return remoteUser(host)
+	}})
+
+	// gh's configuration is read, which unlike gh auth status does not
+	// call the API.
+	if forge == ForgeGithub {
+		sources = append(sources, userSource{"gh", func() string {
+			return commandLine("gh", "config", "get", "--host", GithubDomain, "user")
+		}})
+	}
+
+	return append(sources, userSource{"~/.ssh/config", func() string {
+		return sshConfigUser(host)
+	}})
+}
+
+// remoteUser returns the owner of the origin remote of the repository in
+// the working directory when it is on host, as when the project is
+// generated into a clone.
+func remoteUser(host string) string {
+	remoteHost, owner := parseRemoteURL(commandLine("git", "remote", "get-url", GitRemote))
+	if !strings.EqualFold(remoteHost, host) {
+		return ""
+	}
+
+	return owner
+}
+
+// parseRemoteURL returns the host and owner, the first path element, of a
+// git remote URL: https://host/owner/repo, ssh://git@host/owner/repo or
+// the scp-like git@host:owner/repo.
+func parseRemoteURL(remote string) (string, string) {
+	var host, path string
+
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if address, rest, ok := strings.Cut(remote, ":"); ok && !strings.Contains(address, "/") {
+		host, path = address[strings.LastIndex(address, "@")+1:], rest
+	}
+
+	owner, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	return host, owner
+}
+
+// commandLine returns the trimmed output of a command run in the working
+// directory, or "" when it fails.
+func commandLine(name string, arg ...string) string {
+	out, err := commandOutput("", name, arg...)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// sshConfigUser returns the User of the Host section of the SSH config
+// matching host, unless it is git. OpenSSH reads
+// %USERPROFILE%\.ssh\config on Windows, which is the home directory Go
+// reports there.
+func sshConfigUser(host string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	f, err := os.Open(filepath.Join(home, SSHConfigDir, SSHConfigFile))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inHost := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value := sshConfigLine(scanner.Text())
+
+		switch strings.ToLower(key) {
+		case "host":
+			inHost = false
+
+			for _, pattern := range strings.Fields(value) {
+				if strings.EqualFold(pattern, host) {
+					inHost = true
+				}
+			}
+		case "match":
+			inHost = false
+		case "user":
+			if inHost && value != SSHGitUser {
+				return value
+			}
+		}
+	}
+
+	return ""
+}
+
+// sshConfigLine splits a line of an SSH config into its keyword and
+// arguments, which are separated by whitespace or an equals sign.
+// Comments and blank lines have no keyword.
+func sshConfigLine(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return line, ""
+	}
+
+	value := strings.TrimSpace(line[i:])
+	value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+
+	return line[:i], strings.Trim(value, `"`)
+}
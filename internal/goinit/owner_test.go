@@ -0,0 +1,83 @@
+package goinit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSSHConfigUser(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	if got := sshConfigUser("github.com"); got != "" {
+		t.Errorf("sshConfigUser without a config = %q, want none", got)
+	}
+
+	config := `# Forges
+Host gitlab.com
+  User git
+
+Host github.com gh
+  HostName github.com
+  User=octo
+
+Host bitbucket.org
+	User   atlassian-user
+
+Match host example.com
+  User matched
+
+Host *.internal
+  User internal
+`
+	if err := os.MkdirAll(filepath.Join(home, SSHConfigDir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(home, SSHConfigDir, SSHConfigFile), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"github.com", "octo"},
+		{"GitHub.com", "octo"},
+		{"gh", "octo"},
+		{"bitbucket.org", "atlassian-user"},
+		// git is the user of every forge, not the account.
+		{"gitlab.com", ""},
+		{"example.com", ""},
+		{"codeberg.org", ""},
+	}
+
+	for _, tt := range tests {
+		if got := sshConfigUser(tt.host); got != tt.want {
+			t.Errorf("sshConfigUser(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote      string
+		host, owner string
+	}{
+		{"https://github.com/octo/service.git", "github.com", "octo"},
+		{"https://user@gitlab.com/team/group/service", "gitlab.com", "team"},
+		{"ssh://git@git.example.com:2222/platform/service.git", "git.example.com", "platform"},
+		{"git@github.com:octo/service.git", "github.com", "octo"},
+		{"bitbucket.org:acme/service", "bitbucket.org", "acme"},
+		{"/srv/git/service.git", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		if host, owner := parseRemoteURL(tt.remote); host != tt.host || owner != tt.owner {
+			t.Errorf("parseRemoteURL(%q) = %q, %q, want %q, %q", tt.remote, host, owner, tt.host, tt.owner)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package goinit
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	InitialVersion    = "0.1.0"
+	DefaultMaintainer = "Unknown <unknown@example.com>"
+)
+
+// packageInfo is the project metadata distribution packages are seeded
+// from.
+type packageInfo struct {
+	Name       string
+	Package    string
+	ModulePath string
+	Version    string
+	Maintainer string
+	Date       string
+	RPMDate    string
+}
+
+var invalidPackageChars = regexp.MustCompile(`[^a-z0-9.+-]+`)
+
+func newPackageInfo(dir string, opts options) packageInfo {
+	now := buildTime()
+
+	return packageInfo{
+		Name:       opts.projectName,
+		Package:    packageName(opts.projectName),
+		ModulePath: opts.modulePath(),
+		Version:    InitialVersion,
+		Maintainer: gitMaintainer(dir),
+		Date:       now.Format(time.RFC1123Z),
+		RPMDate:    now.Format("Mon Jan 02 2006"),
+	}
+}
+
+// buildTime returns SOURCE_DATE_EPOCH when set, for reproducible output,
+// and the current time otherwise.
+func buildTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+
+	return time.Now()
+}
+
+// packageName turns a project name into a valid distribution package name:
+// lowercase letters, digits and ".+-".
+func packageName(name string) string {
+	name = invalidPackageChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-.+")
+}
+
+// gitMaintainer returns "Name <email>" from the git configuration of the
+// repository in dir.
+func gitMaintainer(dir string) string {
+	if userConfig.Author != "" {
+		return userConfig.Author
+	}
+
+	name, err := commandOutput(dir, "git", "config", "user.name")
+	if err != nil {
+		return DefaultMaintainer
+	}
+
+	email, err := commandOutput(dir, "git", "config", "user.email")
+	if err != nil {
+		return DefaultMaintainer
+	}
+
+	return strings.TrimSpace(string(name)) + " <" + strings.TrimSpace(string(email)) + ">"
+}
@@ -0,0 +1,52 @@
+package goinit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	BuildTargetsFlag = "build-targets"
+	// DefaultPlatforms are the platforms of the GoReleaser build matrix when
+	// -build-targets is not given.
+	DefaultPlatforms = "linux/amd64,linux/arm64,darwin/amd64,darwin/arm64,windows/amd64,windows/arm64"
+)
+
+var platformPattern = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9]+$`)
+
+// parsePlatforms splits the comma separated GOOS/GOARCH pairs of
+// -build-targets.
+func parsePlatforms(s string) ([]string, error) {
+	var platforms []string
+
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		if !platformPattern.MatchString(p) {
+			return nil, fmt.Errorf("invalid platform %q, -%s takes GOOS/GOARCH pairs such as linux/amd64,darwin/arm64", p, BuildTargetsFlag)
+		}
+
+		platforms = append(platforms, p)
+	}
+
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("no platforms in %q, -%s takes GOOS/GOARCH pairs such as linux/amd64,darwin/arm64", s, BuildTargetsFlag)
+	}
+
+	return platforms, nil
+}
+
+// goreleaserTargets returns the GoReleaser build targets of the platforms,
+// os_arch where the platforms use os/arch.
+func goreleaserTargets(platforms []string) []string {
+	targets := make([]string, len(platforms))
+	for i, p := range platforms {
+		targets[i] = strings.Replace(p, "/", "_", 1)
+	}
+
+	return targets
+}
@@ -0,0 +1,31 @@
+package goinit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+		ok   bool
+	}{
+		{"linux/amd64", []string{"linux/amd64"}, true},
+		{"linux/amd64,darwin/arm64", []string{"linux/amd64", "darwin/arm64"}, true},
+		{" linux/amd64 , ,windows/386,", []string{"linux/amd64", "windows/386"}, true},
+		{"", nil, false},
+		{" , ", nil, false},
+		{"linux", nil, false},
+		{"linux/amd64/v3", nil, false},
+		{"Linux/amd64", nil, false},
+		{"linux_amd64", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePlatforms(tt.s)
+		if (err == nil) != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePlatforms(%q) = %q, %v, want %q, ok %t", tt.s, got, err, tt.want, tt.ok)
+		}
+	}
+}
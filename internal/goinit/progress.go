@@ -0,0 +1,159 @@
+package goinit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	NoColorFlag   = "no-color"
+	SpinnerPeriod = 100 * time.Millisecond
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// steps reports the generation steps. It writes nothing until main points it
+// at the terminal, so the web UI and diff generate silently.
+var steps = newProgress(io.Discard, false, false)
+
+// progress shows the step being run. On a terminal the step has a spinner
+// and is marked done or failed in place; otherwise each step is a line.
+type progress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	tty     bool
+	color   bool
+	step    string
+	started time.Time
+	frame   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func newProgress(w io.Writer, tty, color bool) *progress {
+	return &progress{w: w, tty: tty, color: color}
+}
+
+// newTerminalProgress writes to stderr, with a spinner when it is a
+// terminal and colors unless disabled or NO_COLOR is set.
+func newTerminalProgress(noColor bool) *progress {
+	tty := isTerminal(os.Stderr) && os.Getenv("TERM") != "dumb"
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+
+	return newProgress(os.Stderr, tty, tty && !noColor && !noColorEnv)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// start marks the current step done and begins the next one.
+func (p *progress) start(step string) {
+	p.end("✓", "32")
+	logs.step(step)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.step, p.started, p.frame = step, time.Now(), 0
+
+	if !p.tty {
+		fmt.Fprintf(p.w, "- %s\n", step)
+		return
+	}
+
+	p.draw()
+
+	p.stop, p.stopped = make(chan struct{}), make(chan struct{})
+	go p.spin(p.stop, p.stopped)
+}
+
+// finish marks the current step done.
+func (p *progress) finish() {
+	p.end("✓", "32")
+}
+
+// fail marks the current step failed.
+func (p *progress) fail() {
+	p.end("✗", "31")
+}
+
+func (p *progress) end(mark, color string) {
+	p.mu.Lock()
+	stop, stopped := p.stop, p.stopped
+	p.stop, p.stopped = nil, nil
+	p.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.step == "" {
+		return
+	}
+
+	if p.tty {
+		elapsed := time.Since(p.started).Round(100 * time.Millisecond)
+		fmt.Fprintf(p.w, "\r\033[K%s %s %s\n", p.paint(mark, color), p.step, p.paint(fmt.Sprintf("(%s)", elapsed), "2"))
+	}
+
+	p.step = ""
+}
+
+func (p *progress) spin(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(SpinnerPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame++
+			p.draw()
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *progress) draw() {
+	frame := spinnerFrames[p.frame%len(spinnerFrames)]
+	fmt.Fprintf(p.w, "\r\033[K%s %s", p.paint(frame, "36"), p.step)
+}
+
+func (p *progress) paint(s, color string) string {
+	if !p.color {
+		return s
+	}
+
+	return "\033[" + color + "m" + s + "\033[0m"
+}
+
+// Write prints log output above the spinner, so warnings logged during a
+// step are not overwritten by it.
+func (p *progress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.tty || p.step == "" {
+		return p.w.Write(b)
+	}
+
+	fmt.Fprint(p.w, "\r\033[K")
+
+	n, err := p.w.Write(b)
+	p.draw()
+
+	return n, err
+}
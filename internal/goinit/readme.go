@@ -0,0 +1,86 @@
+package goinit
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	ReadmeFile      = "README.md"
+	ReadmeTemplate  = "templates/README.md.tmpl"
+	NoReadmeFlag    = "no-readme"
+	ComponentReadme = "readme"
+)
+
+// makeTargetPattern matches the rules of a Makefile, leaving out variable
+// assignments and special targets such as .PHONY.
+var makeTargetPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_./-]*)\s*:([^=]|$)`)
+
+type readmeData struct {
+	projectContext
+	// Install is the go command installing the project.
+	Install string
+	// Workflow is the file name of the CI workflow, empty when the project
+	// has none.
+	Workflow string
+	// Targets are the targets of the task runner's file, TargetsFile, in
+	// the order they are defined.
+	Targets     []string
+	TargetsFile string
+	License     bool
+}
+
+// createReadme writes a README with the project's badges, installation and
+// targets. It reads the task runner's file, so it runs once every option has
+// added its targets.
+func createReadme(dir string, opts options) error {
+	ctx := newProjectContext(dir, opts)
+	data := readmeData{
+		projectContext: ctx,
+		Install:        "go install " + ctx.ModulePath + strings.TrimPrefix(ctx.MainPackage, ".") + "@latest",
+		Targets:        runnerTargets(dir, ctx.TaskRunner),
+		TargetsFile:    taskRunners[ctx.TaskRunner].file,
+		License:        opts.license != "" || exists(filepath.Join(dir, LicenseFile)),
+	}
+
+	if opts.projectType == TypeLib {
+		data.Install = "go get " + ctx.ModulePath
+	}
+
+	if opts.ciProvider() == CIGithub {
+		data.Workflow = path.Base(CIWorkflowFile)
+	}
+
+	return renderFile(dir, ReadmeFile, templatesFS, ReadmeTemplate, data)
+}
+
+// makeTargets returns the targets of the Makefile name, or none when there
+// is no Makefile.
+func makeTargets(name string) []string {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var targets []string
+
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := makeTargetPattern.FindStringSubmatch(scanner.Text())
+		if m == nil || seen[m[1]] {
+			continue
+		}
+
+		seen[m[1]] = true
+		targets = append(targets, m[1])
+	}
+
+	return targets
+}
@@ -0,0 +1,374 @@
+package goinit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	GoinitRepo        = "AlexEkdahl/goinit"
+	ChecksumsAsset    = "checksums.txt"
+	SignatureSuffix   = ".sig"
+	CertificateSuffix = ".pem"
+	// ReleaseIdentity is the workflow expected to have signed the
+	// checksums, through GitHub's OIDC token.
+	ReleaseIdentity = "^https://github.com/" + GoinitRepo + "/.github/workflows/releaser.yml@"
+	ReleaseIssuer   = "https://token.actions.githubusercontent.com"
+	// InsecureSkipSignatureFlag updates verifying the checksum only, for
+	// releases that are not signed or machines without cosign.
+	InsecureSkipSignatureFlag = "insecure-skip-signature"
+)
+
+// version is the release goinit was built from, set with -X by the
+// ldflags of .goreleaser.yml and the Makefile's build.
+var version = "dev"
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+type release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+func (r release) asset(name string) (releaseAsset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+
+	return releaseAsset{}, false
+}
+
+// selfUpdate replaces the running binary with the latest release when it
+// is newer, after checking it against the release's checksums and their
+// signature.
+func selfUpdate(args []string) error {
+	set := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	check := set.Bool("check", false, "only report whether a newer release is available")
+	skipSignature := set.Bool(InsecureSkipSignatureFlag, false, "verify the checksum only, without cosign checking its signature")
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	client := &githubClient{
+		baseURL: GithubAPIURL,
+		token:   os.Getenv("GITHUB_TOKEN"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	var latest release
+	if err := client.do(http.MethodGet, "/repos/"+GoinitRepo+"/releases/latest", nil, &latest); err != nil {
+		return fmt.Errorf("error reading the latest release: %w", err)
+	}
+
+	current := currentVersion()
+	if !isNewer(latest.TagName, current) {
+		log.Printf("goinit %s is up to date", current)
+		return nil
+	}
+
+	if *check {
+		log.Printf("goinit %s is available, running %s", latest.TagName, current)
+		return nil
+	}
+
+	binary, err := downloadRelease(client.client, latest, *skipSignature)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceExecutable(binary); err != nil {
+		return err
+	}
+
+	log.Printf("Updated goinit from %s to %s", current, latest.TagName)
+
+	return nil
+}
+
+// currentVersion returns the version set at build time, or the module
+// version of a binary built with go install.
+func currentVersion() string {
+	if version != "dev" {
+		return version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	return version
+}
+
+// isNewer reports whether latest is a later version than current. A
+// development build is older than any release.
+func isNewer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+
+	c, ok := parseVersion(current)
+	if !ok {
+		return true
+	}
+
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+
+	return false
+}
+
+// parseVersion parses the major, minor and patch numbers of a vX.Y.Z
+// version, ignoring any pre-release or build suffix.
+func parseVersion(v string) ([3]int, bool) {
+	var parsed [3]int
+
+	v, _, _ = strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	v, _, _ = strings.Cut(v, "+")
+
+	parts := strings.Split(v, ".")
+	if len(parts) != len(parsed) {
+		return parsed, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+
+		parsed[i] = n
+	}
+
+	return parsed, true
+}
+
+// assetName is the name GoReleaser gives the binary for this platform.
+func assetName(tag string) string {
+	name := fmt.Sprintf("goinit_%s_%s_%s", strings.TrimPrefix(tag, "v"), runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	return name
+}
+
+// downloadRelease downloads the binary for this platform from r and
+// verifies it.
+func downloadRelease(client *http.Client, r release, skipSignature bool) ([]byte, error) {
+	name := assetName(r.TagName)
+
+	asset, ok := r.asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no binary for %s/%s", r.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+
+	checksumsAsset, ok := r.asset(ChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s to verify the binary with", r.TagName, ChecksumsAsset)
+	}
+
+	checksums, err := download(client, checksumsAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(client, r, checksums, skipSignature); err != nil {
+		return nil, err
+	}
+
+	binary, err := download(client, asset.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyChecksum(checksums, name, binary); err != nil {
+		return nil, err
+	}
+
+	return binary, nil
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(rootCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading %s: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", url, err)
+	}
+
+	return body, nil
+}
+
+// verifyChecksum checks data against the SHA-256 listed for name in a
+// sha256sum formatted checksums file.
+func verifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != fields[0] {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%s has no checksum for %s", ChecksumsAsset, name)
+}
+
+// verifySignature checks the keyless cosign signature of the checksums,
+// proving they were produced by goinit's release workflow. It fails when
+// the signature cannot be verified, without cosign installed or for
+// releases published before signing, unless skip is set.
+func verifySignature(client *http.Client, r release, checksums []byte, skip bool) error {
+	if skip {
+		log.Printf("Not verifying the signature of release %s, verifying the checksum only", r.TagName)
+		return nil
+	}
+
+	signature, hasSignature := r.asset(ChecksumsAsset + SignatureSuffix)
+	certificate, hasCertificate := r.asset(ChecksumsAsset + CertificateSuffix)
+
+	if !hasSignature || !hasCertificate {
+		return fmt.Errorf("release %s is not signed, pass -%s to verify the checksum only", r.TagName, InsecureSkipSignatureFlag)
+	}
+
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return fmt.Errorf("cosign verifies the signature of release %s, install it or pass -%s to verify the checksum only", r.TagName, InsecureSkipSignatureFlag)
+	}
+
+	dir, err := os.MkdirTemp("", "goinit-update-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string][]byte{ChecksumsAsset: checksums}
+
+	for _, asset := range []releaseAsset{signature, certificate} {
+		if files[asset.Name], err = download(client, asset.URL); err != nil {
+			return err
+		}
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return fmt.Errorf("error writing %s: %w", name, err)
+		}
+	}
+
+	var out []byte
+
+	cmd, run := command(networkTimeout, "cosign", "verify-blob",
+		"--signature", filepath.Join(dir, signature.Name),
+		"--certificate", filepath.Join(dir, certificate.Name),
+		"--certificate-identity-regexp", ReleaseIdentity,
+		"--certificate-oidc-issuer", ReleaseIssuer,
+		filepath.Join(dir, ChecksumsAsset),
+	)
+
+	err = run(func() (err error) {
+		out, err = cmd.CombinedOutput()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error verifying the signature of %s: %s", ChecksumsAsset, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// replaceExecutable writes binary next to the running executable and
+// renames it over it, so the replacement is atomic.
+func replaceExecutable(binary []byte) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error locating the executable: %w", err)
+	}
+
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("error locating the executable: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".goinit-update-")
+	if err != nil {
+		return fmt.Errorf("error writing the update: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing the update: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing the update: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return fmt.Errorf("error writing the update: %w", err)
+	}
+
+	// Windows cannot replace a running executable, but can rename it.
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		_ = os.Remove(old)
+
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("error replacing %s: %w", exe, err)
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("no permission to replace %s, rerun with sufficient privileges", exe)
+		}
+
+		return fmt.Errorf("error replacing %s: %w", exe, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package goinit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    [3]int
+		ok      bool
+	}{
+		{"v1.2.3", [3]int{1, 2, 3}, true},
+		{"1.2.3", [3]int{1, 2, 3}, true},
+		{"v1.10.0-rc.1", [3]int{1, 10, 0}, true},
+		{"v2.0.1+dirty", [3]int{2, 0, 1}, true},
+		{"v1.2", [3]int{}, false},
+		{"v1.2.3.4", [3]int{}, false},
+		{"v1.x.3", [3]int{}, false},
+		{"dev", [3]int{}, false},
+		{"", [3]int{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseVersion(tt.version)
+		if ok != tt.ok || ok && got != tt.want {
+			t.Errorf("parseVersion(%q) = %v, %t, want %v, %t", tt.version, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.4", "v1.2.3", true},
+		{"v1.3.0", "v1.2.9", true},
+		{"v2.0.0", "v1.9.9", true},
+		{"v1.10.0", "v1.9.0", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.2.3", "v1.2.4", false},
+		{"v1.2.3", "v1.2.3-rc.1", false},
+		// A development build is always behind a release.
+		{"v1.2.3", "dev", true},
+		{"latest", "v1.2.3", false},
+	}
+
+	for _, tt := range tests {
+		if got := isNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("isNewer(%q, %q) = %t, want %t", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("goinit\n")
+	sum := checksum(data)
+
+	checksums := []byte("0000  goinit_1.0.0_linux_arm64\n" + sum + "  goinit_1.0.0_linux_amd64\n")
+
+	if err := verifyChecksum(checksums, "goinit_1.0.0_linux_amd64", data); err != nil {
+		t.Errorf("verifyChecksum of a matching file = %v, want nil", err)
+	}
+
+	if err := verifyChecksum(checksums, "goinit_1.0.0_linux_amd64", []byte("tampered\n")); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("verifyChecksum of a different file = %v, want a checksum mismatch", err)
+	}
+
+	if err := verifyChecksum(checksums, "goinit_1.0.0_darwin_arm64", data); err == nil || !strings.Contains(err.Error(), ChecksumsAsset) {
+		t.Errorf("verifyChecksum of a missing file = %v, want an error naming %s", err, ChecksumsAsset)
+	}
+}
+
+func TestVerifySignatureUnverifiable(t *testing.T) {
+	unsigned := release{TagName: "v1.0.0", Assets: []releaseAsset{{Name: ChecksumsAsset}}}
+	signed := release{TagName: "v1.0.0", Assets: []releaseAsset{
+		{Name: ChecksumsAsset},
+		{Name: ChecksumsAsset + SignatureSuffix},
+		{Name: ChecksumsAsset + CertificateSuffix},
+	}}
+
+	// Without cosign on the PATH.
+	t.Setenv("PATH", t.TempDir())
+
+	for _, r := range []release{unsigned, signed} {
+		if err := verifySignature(nil, r, nil, false); err == nil || !strings.Contains(err.Error(), InsecureSkipSignatureFlag) {
+			t.Errorf("verifySignature(%v) = %v, want an error suggesting -%s", r.Assets, err, InsecureSkipSignatureFlag)
+		}
+
+		if err := verifySignature(nil, r, nil, true); err != nil {
+			t.Errorf("verifySignature(%v) skipping the signature = %v, want nil", r.Assets, err)
+		}
+	}
+}
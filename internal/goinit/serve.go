@@ -0,0 +1,425 @@
+package goinit
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultServeAddr = "127.0.0.1:8080"
+	ProjectNameFlag  = "d"
+	CSRFField        = "csrf"
+)
+
+// serveExcluded are the flags the web UI leaves out: those acting on the
+// GitHub account with its token, pushing, or cloning templates from any
+// URL.
+var serveExcluded = map[string]bool{
+	"create-remote": true,
+	"private":       true,
+	"push":          true,
+	"labels":        true,
+	"protect":       true,
+	TemplateFlag:    true,
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>goinit</title>
+<style>
+  body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; }
+  label { display: block; margin: .4rem 0; }
+  small { color: #555; }
+  .error { color: #b00; }
+</style>
+</head>
+<body>
+<h1>goinit</h1>
+{{if .Message}}<p class="{{if .Failed}}error{{end}}">{{.Message}}</p>{{end}}
+<form method="post" action="/generate">
+  <input type="hidden" name="csrf" value="{{.Token}}">
+  <label>Project name <input name="d" value="{{.ProjectName}}" required></label>
+  <label>Directory <input name="dir" value="{{.Dir}}" size="50">
+    <small>the project is created inside this directory of {{.Root}}</small></label>
+  <h2>Options</h2>
+  {{range .Fields}}
+  <label>
+    {{if .Bool}}<input type="checkbox" name="{{.Name}}">{{.Name}}
+    {{else}}{{.Name}} <input name="{{.Name}}" value="{{.Value}}">{{end}}
+    <small>{{.Usage}}</small>
+  </label>
+  {{end}}
+  <p>
+    <button type="submit">Generate</button>
+    <button type="submit" name="zip" value="1">Download zip</button>
+  </p>
+</form>
+</body>
+</html>
+`))
+
+type formField struct {
+	Name  string
+	Usage string
+	Value string
+	Bool  bool
+}
+
+type page struct {
+	ProjectName string
+	Dir         string
+	Root        string
+	Token       string
+	Fields      []formField
+	Message     string
+	Failed      bool
+}
+
+// webUI serves the form. Only pages requested by the address it listens on
+// are served, so other sites cannot read them through DNS rebinding, and
+// generating needs the token of the form, so they cannot post it either.
+type webUI struct {
+	// root is the directory the projects are generated under.
+	root string
+	// hosts are the host:port values requests may name.
+	hosts map[string]bool
+	token string
+}
+
+// generateMu serializes generation, which sets the root context and the
+// template variables of the project.
+var generateMu sync.Mutex
+
+// serve runs a local web UI presenting the generation options as a form.
+func serve(args []string) error {
+	set := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := set.String("addr", DefaultServeAddr, "loopback address to listen on")
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(*addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", *addr, err)
+	}
+
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("the web UI generates projects on this machine, so it only listens on a loopback address such as %s", DefaultServeAddr)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current working directory: %w", err)
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("error creating the form token: %w", err)
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	ui := webUI{
+		root:  root,
+		hosts: map[string]bool{*addr: true, ln.Addr().String(): true, net.JoinHostPort("localhost", port): true},
+		token: hex.EncodeToString(token),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", ui.handleIndex)
+	mux.HandleFunc("/generate", ui.handleGenerate)
+
+	log.Printf("Serving goinit on http://%s", ln.Addr())
+
+	return http.Serve(ln, ui.checkHost(mux))
+}
+
+// checkHost rejects requests naming another host than the UI's.
+func (ui webUI) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ui.hosts[r.Host] {
+			http.Error(w, "unexpected host "+r.Host, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkForm rejects forms posted by other origins or without the token.
+func (ui webUI) checkForm(r *http.Request) error {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme != "http" || !ui.hosts[u.Host] {
+			return fmt.Errorf("form posted from %s", origin)
+		}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.PostFormValue(CSRFField)), []byte(ui.token)) != 1 {
+		return errors.New("the form has expired, reload the page")
+	}
+
+	return nil
+}
+
+// projectDir returns the directory of the form's dir, which has to be
+// under the root.
+func (ui webUI) projectDir(dir string) (string, error) {
+	if filepath.IsAbs(dir) || filepath.VolumeName(dir) != "" {
+		return "", fmt.Errorf("directory %q has to be relative to %s", dir, ui.root)
+	}
+
+	path := filepath.Join(ui.root, dir)
+
+	// A symbolic link may point out of the root, so an existing
+	// directory is checked where it resolves to.
+	root, resolved := ui.root, path
+	if r, err := filepath.EvalSymlinks(ui.root); err == nil {
+		root = r
+	}
+
+	if r, err := filepath.EvalSymlinks(path); err == nil {
+		resolved = r
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("directory %q is outside of %s", dir, ui.root)
+	}
+
+	return path, nil
+}
+
+func (ui webUI) newPage() page {
+	var opts options
+
+	set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+	registerFlags(set, &opts)
+
+	p := page{ProjectName: DefaultProjectName, Dir: ".", Root: ui.root, Token: ui.token}
+
+	set.VisitAll(func(f *flag.Flag) {
+		if f.Name == ProjectNameFlag || serveExcluded[f.Name] {
+			return
+		}
+
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		p.Fields = append(p.Fields, formField{
+			Name:  f.Name,
+			Usage: f.Usage,
+			Value: f.DefValue,
+			Bool:  ok && b.IsBoolFlag(),
+		})
+	})
+
+	return p
+}
+
+func (ui webUI) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	render(w, http.StatusOK, ui.newPage())
+}
+
+func (ui webUI) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	p := ui.newPage()
+
+	if err := ui.checkForm(r); err != nil {
+		p.Message, p.Failed = err.Error(), true
+		render(w, http.StatusForbidden, p)
+
+		return
+	}
+
+	opts, err := parseForm(r, p.Fields)
+	if err != nil {
+		p.Message, p.Failed = err.Error(), true
+		render(w, http.StatusBadRequest, p)
+
+		return
+	}
+
+	if r.FormValue("zip") != "" {
+		if err := generateZip(w, opts); err != nil {
+			log.Printf("Error generating zip: %v", err)
+		}
+
+		return
+	}
+
+	p.Dir = r.FormValue("dir")
+
+	dir, err := ui.projectDir(p.Dir)
+	if err != nil {
+		p.Message, p.Failed = err.Error(), true
+		render(w, http.StatusBadRequest, p)
+
+		return
+	}
+
+	if err := generateIn(rootCtx, dir, opts); err != nil {
+		p.Message, p.Failed = err.Error(), true
+		render(w, http.StatusInternalServerError, p)
+
+		return
+	}
+
+	p.Message = "Created " + filepath.Join(dir, opts.projectName)
+	render(w, http.StatusOK, p)
+}
+
+// parseForm turns the submitted form back into command line arguments, so
+// the options go through the same parsing and validation as the CLI.
+func parseForm(r *http.Request, fields []formField) (options, error) {
+	var opts options
+
+	if err := r.ParseForm(); err != nil {
+		return opts, fmt.Errorf("error parsing form: %w", err)
+	}
+
+	args := []string{"-" + ProjectNameFlag + "=" + r.FormValue(ProjectNameFlag)}
+
+	for _, f := range fields {
+		if f.Bool {
+			args = append(args, "-"+f.Name+"="+strconv.FormatBool(r.FormValue(f.Name) != ""))
+		} else {
+			args = append(args, "-"+f.Name+"="+r.FormValue(f.Name))
+		}
+	}
+
+	set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	registerFlags(set, &opts)
+
+	if err := set.Parse(args); err != nil {
+		return opts, err
+	}
+
+	opts.args = changedArgs(set)
+
+	return opts, opts.validate()
+}
+
+// generateIn creates the project inside dir, running its commands under
+// ctx.
+func generateIn(ctx context.Context, dir string, opts options) error {
+	generateMu.Lock()
+	defer generateMu.Unlock()
+
+	previous := rootCtx
+	rootCtx = ctx
+	defer func() { rootCtx = previous }()
+
+	return generateProject(dir, opts)
+}
+
+// generateZip creates the project in a temporary directory and streams it
+// to w as a zip archive.
+func generateZip(w http.ResponseWriter, opts options) error {
+	tmp, err := os.MkdirTemp("", "goinit-")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := generateIn(rootCtx, tmp, opts); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", opts.projectName+".zip"))
+
+	return writeZip(w, tmp)
+}
+
+func writeZip(w io.Writer, root string) error {
+	zw := zip.NewWriter(w)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(rel)
+		header.Method = zip.Deflate
+
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		_, err = io.Copy(dst, src)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error writing zip: %w", err)
+	}
+
+	return zw.Close()
+}
+
+func render(w http.ResponseWriter, status int, p page) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if err := pageTemplate.Execute(w, p); err != nil {
+		log.Printf("Error rendering page: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package goinit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// SnapshotPackage is the package of the snapshot tests, relative to a
+// goinit checkout.
+const SnapshotPackage = "./internal/goinit"
+
+// templatesCommand runs the template maintenance commands.
+func templatesCommand(args []string) error {
+	if len(args) == 0 || args[0] != "test" {
+		return errors.New("usage: goinit templates test [-update] [-run regexp]")
+	}
+
+	return testSnapshots(args[1:])
+}
+
+// testSnapshots runs the snapshot tests of a goinit checkout in the
+// working directory, which compare the project of every snapshot case
+// against its golden snapshot, or with -update rewrite the snapshots.
+func testSnapshots(args []string) error {
+	set := flag.NewFlagSet("templates test", flag.ContinueOnError)
+	update := set.Bool("update", false, "rewrite the golden snapshots")
+	run := set.String("run", "", "only run the cases matching the regular expression")
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	pattern := "^TestSnapshots$"
+	if *run != "" {
+		pattern += "/" + *run
+	}
+
+	testArgs := []string{"test", "-count=1", "-timeout=30m", "-run", pattern, SnapshotPackage}
+	if *update {
+		testArgs = append(testArgs, "-args", "-update")
+	}
+
+	cmd := exec.CommandContext(rootCtx, "go", testArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if rootCtx.Err() != nil {
+			return errInterrupted
+		}
+
+		return fmt.Errorf("error running the snapshot tests, run with -update if the changes are intended: %w", err)
+	}
+
+	return nil
+}
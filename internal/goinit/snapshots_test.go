@@ -0,0 +1,343 @@
+package goinit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const (
+	SnapshotDir     = "testdata/golden"
+	SnapshotExt     = ".golden"
+	SnapshotProject = "snapshot"
+)
+
+var updateSnapshots = flag.Bool("update", false, "rewrite the golden snapshots")
+
+// snapshotCase is a combination of options whose generated project is
+// compared against its golden snapshot.
+type snapshotCase struct {
+	Name string
+	Args []string
+}
+
+// snapshotCases cover every option and option value. -labels, -protect
+// and -create-remote call the GitHub API and -tools depends on the
+// installed Go version, so they are left out, as is -go-version, whose
+// versions are normalized, and -commit, which leaves the files as they are.
+var snapshotCases = []snapshotCase{
+	{"default", nil},
+	{"ratelimit", []string{"-ratelimit"}},
+	{"cors", []string{"-cors"}},
+	{"assets", []string{"-assets"}},
+	{"i18n", []string{"-i18n"}},
+	{"flags-stdlib", []string{"-flags=stdlib"}},
+	{"flags-pflag", []string{"-flags=pflag"}},
+	{"flags-urfave", []string{"-flags=urfave"}},
+	{"flags-kong", []string{"-flags=kong"}},
+	{"automation", []string{"-automation"}},
+	{"release-notes-drafter", []string{"-release-notes=drafter"}},
+	{"release-semantic-release", []string{"-release=semantic-release"}},
+	{"provenance", []string{"-provenance"}},
+	{"buildx-trivy", []string{"-buildx", "-trivy"}},
+	{"docker", []string{"-docker"}},
+	{"no-git", []string{"-no-git"}},
+	{"editor-vscode", []string{"-editor=vscode"}},
+	{"devcontainer", []string{"-devcontainer"}},
+	{"hooks-pre-commit-framework", []string{"-hooks=pre-commit-framework"}},
+	{"hooks-lefthook", []string{"-hooks=lefthook"}},
+	{"registry-artifactory", []string{"-registry=artifactory", "-buildx"}},
+	{"registry-nexus", []string{"-registry=nexus"}},
+	{"aur", []string{"-aur"}},
+	{"debian", []string{"-debian"}},
+	{"rpm", []string{"-rpm"}},
+	{"chocolatey", []string{"-chocolatey"}},
+	{"mocks-mockery", []string{"-mocks=mockery"}},
+	{"mocks-mockgen", []string{"-mocks=mockgen"}},
+	{"di-wire", []string{"-di=wire"}},
+	{"di-fx", []string{"-di=fx"}},
+	{"sops", []string{"-sops"}},
+	{"environments", []string{"-environments"}},
+	{"layout-operator", []string{"-layout=operator"}},
+	{"layout-tf-provider", []string{"-layout=tf-provider"}},
+	{"layout-github-app", []string{"-layout=github-app"}},
+	{"layout-bot-slack", []string{"-layout=bot", "-platform=slack"}},
+	{"layout-bot-discord", []string{"-layout=bot", "-platform=discord"}},
+	{"layout-cronjob", []string{"-layout=cronjob", "-k8s"}},
+	{"layout-desktop-fyne", []string{"-layout=desktop", "-framework=fyne"}},
+	{"layout-desktop-wails", []string{"-layout=desktop", "-framework=wails"}},
+	{"layout-mobile", []string{"-layout=mobile"}},
+	{"layout-mcp", []string{"-layout=mcp"}},
+	{"layout-ssh-app", []string{"-layout=ssh-app"}},
+	{"type-cli", []string{"-type=cli"}},
+	{"type-lib", []string{"-type=lib"}},
+	{"type-api", []string{"-type=api"}},
+	{"type-api-chi", []string{"-type=api", "-router=chi"}},
+	{"type-api-echo", []string{"-type=api", "-router=echo"}},
+	{"type-api-gin", []string{"-type=api", "-router=gin", "-docker"}},
+	{"type-grpc", []string{"-type=grpc"}},
+	{"type-openapi", []string{"-type=openapi"}},
+	{"type-api-db-postgres", []string{"-type=api", "-db=postgres"}},
+	{"type-api-db-mysql", []string{"-type=api", "-router=echo", "-db=mysql", "-migrations=golang-migrate"}},
+	{"type-api-db-sqlite", []string{"-type=api", "-router=gin", "-db=sqlite", "-migrations=golang-migrate"}},
+	{"compose", []string{"-compose"}},
+	{"compose-api-db", []string{"-type=api", "-db=postgres", "-compose", "-compose-services=postgres,redis"}},
+	{"compose-api-sqlite", []string{"-type=api", "-db=sqlite", "-compose"}},
+	{"compose-grpc-task", []string{"-type=grpc", "-compose", "-compose-services=redis", "-task-runner=task"}},
+	{"type-worker", []string{"-type=worker"}},
+	{"module", []string{"-module=example.com/team/snapshot"}},
+	{"module-github", []string{"-module=github.com/octo/snapshot"}},
+	{"license-mit", []string{"-license=mit"}},
+	{"license-apache-2.0", []string{"-license=apache-2.0"}},
+	{"license-bsd-3-clause", []string{"-license=bsd-3-clause"}},
+	{"ci-gitlab", []string{"-ci=gitlab", "-module=gitlab.com/team/snapshot"}},
+	{"ci-circleci", []string{"-ci=circleci"}},
+	{"ci-bitbucket", []string{"-ci=bitbucket"}},
+	{"host-gitlab", []string{"-host=gitlab.com"}},
+	{"host-bitbucket", []string{"-host=bitbucket.org"}},
+	{"ci-none", []string{"-ci=none"}},
+	{"lint-standard", []string{"-lint=standard"}},
+	{"lint-minimal", []string{"-lint=minimal"}},
+	{"lint-none", []string{"-lint=none"}},
+	{"skip-all", []string{"-skip=makefile,ci,hooks"}},
+	{"skip-makefile-targets", []string{"-skip=makefile", "-type=grpc", "-docker"}},
+	{"workspace", []string{"-workspace"}},
+	{"build-targets", []string{"-build-targets=linux/amd64,darwin/arm64,windows/amd64"}},
+	{"task-runner-task", []string{"-task-runner=task", "-type=api"}},
+	{"task-runner-just", []string{"-task-runner=just", "-type=grpc", "-docker", "-github-community"}},
+	{"task-runner-task-openapi", []string{"-task-runner=task", "-type=openapi"}},
+	{"task-runner-task-workspace", []string{"-task-runner=task", "-workspace", "-type=lib"}},
+	{"goreleaser-options", []string{"-brew-tap=acme/homebrew-tap", "-docker-images", "-nfpm", "-build-targets=linux/amd64,linux/arm64,darwin/arm64", "-module=github.com/acme/snapshot"}},
+	{"goreleaser-options-worker", []string{"-type=worker", "-nfpm", "-docker-images", "-module=example.com/snapshot", "-ci=github"}},
+	{"supply-chain", []string{"-supply-chain", "-docker-images", "-module=github.com/acme/snapshot"}},
+	{"changelog-release-please", []string{"-changelog=release-please", "-supply-chain", "-brew-tap=acme/homebrew-tap"}},
+	{"changelog-git-cliff", []string{"-changelog=git-cliff"}},
+	{"github-community", []string{"-github-community"}},
+	{"no-readme", []string{"-no-readme"}},
+}
+
+// snapshotEnv isolates generation from the machine it runs on: no network,
+// no user or global git configuration and a fixed date.
+var snapshotEnv = map[string]string{
+	"GOPROXY":             "off",
+	"GOFLAGS":             "-mod=mod",
+	"GOSUMDB":             "off",
+	"GIT_CONFIG_NOSYSTEM": "1",
+	"SOURCE_DATE_EPOCH":   "0",
+	// An unreachable proxy keeps the other tools offline, such as buf
+	// generating the code of the grpc type.
+	"HTTPS_PROXY": "http://127.0.0.1:9",
+}
+
+// snapshotNormalizers replace what still differs between runs.
+var snapshotNormalizers = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// The go directive follows the installed toolchain.
+	{regexp.MustCompile(`(?m)^go 1\.\d+(\.\d+)?$\n?(toolchain .*\n)?`), "go 1.x\n"},
+	{regexp.MustCompile(`(?m)^(\s+go-version: )'1\.\d+(\.\d+)?'$`), "${1}'1.x'"},
+	{regexp.MustCompile(`(?m)^(\s+- )'1\.\d+(\.\d+)?'$`), "${1}'1.x'"},
+	{regexp.MustCompile(`((?:golang|cimg/go):)1\.\d+(\.\d+)?`), "${1}1.x"},
+	{regexp.MustCompile(`(?m)^(GO_VERSION \?= )1\.\d+(\.\d+)?$`), "${1}1.x"},
+	{regexp.MustCompile(`(?m)^(ARG GO_VERSION=)1\.\d+(\.\d+)?$`), "${1}1.x"},
+	{regexp.MustCompile(`requires Go 1\.\d+(\.\d+)?`), "requires Go 1.x"},
+	// Random UUIDs, such as the MSI upgrade code.
+	{regexp.MustCompile(`[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}`), "00000000-0000-0000-0000-000000000000"},
+}
+
+// TestSnapshots generates the project of every snapshot case and compares
+// it against its golden snapshot, or with -update rewrites the snapshots.
+func TestSnapshots(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating the snapshot projects is slow")
+	}
+
+	for _, tool := range []string{"git", "go"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s is not installed", tool)
+		}
+	}
+
+	dir, err := absPath(SnapshotDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isolateSnapshotEnv(t)
+
+	for _, c := range snapshotCases {
+		c := c
+
+		t.Run(c.Name, func(t *testing.T) {
+			got := renderSnapshot(t, generateSnapshot(t, c))
+			golden := filepath.Join(dir, c.Name+SnapshotExt)
+
+			if *updateSnapshots {
+				if err := ensureDir(dir); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("error reading snapshot, run with -update to create it: %v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				var diff strings.Builder
+				writeUnifiedDiff(&diff, "template/"+c.Name+SnapshotExt, "project/"+c.Name+SnapshotExt, splitLines(string(want)), splitLines(string(got)), DefaultDiffContext)
+				t.Errorf("snapshot differs, run with -update if the changes are intended:\n%s", diff.String())
+			}
+		})
+	}
+}
+
+// isolateSnapshotEnv sets snapshotEnv and an empty home directory, module
+// cache and configuration for the rest of the test, which runs in an empty
+// directory, outside goinit's repository and its remote.
+func isolateSnapshotEnv(t *testing.T) {
+	home := t.TempDir()
+	chdir(t, t.TempDir())
+
+	env := map[string]string{
+		"HOME":              home,
+		"USERPROFILE":       home,
+		"XDG_CONFIG_HOME":   filepath.Join(home, ".config"),
+		"GIT_CONFIG_GLOBAL": filepath.Join(home, ".gitconfig"),
+		"GH_CONFIG_DIR":     filepath.Join(home, ".config", "gh"),
+		"GOMODCACHE":        filepath.Join(home, "pkg", "mod"),
+	}
+
+	for key, value := range snapshotEnv {
+		env[key] = value
+	}
+
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	logOutput := log.Writer()
+	log.SetOutput(io.Discard)
+
+	config := userConfig
+	userConfig = userConfiguration{}
+
+	t.Cleanup(func() {
+		userConfig = config
+		log.SetOutput(logOutput)
+
+		// The module cache is read-only, which keeps the temporary
+		// directory from being removed.
+		filepath.WalkDir(home, func(path string, _ os.DirEntry, _ error) error {
+			os.Chmod(path, 0o755)
+			return nil
+		})
+	})
+}
+
+// generateSnapshot generates the project of c and returns its files.
+func generateSnapshot(t *testing.T, c snapshotCase) fstest.MapFS {
+	t.Helper()
+
+	var opts options
+
+	set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	registerFlags(set, &opts)
+
+	if err := set.Parse(append([]string{"-" + ProjectNameFlag + "=" + SnapshotProject}, c.Args...)); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.args = changedArgs(set)
+
+	if err := opts.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := t.TempDir()
+
+	if err := generateIn(rootCtx, tmp, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	root := filepath.Join(tmp, SnapshotProject)
+
+	files, err := walkFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := make(fstest.MapFS, len(files))
+
+	for name := range files {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fsys[name] = &fstest.MapFile{Data: data}
+	}
+
+	return fsys
+}
+
+// renderSnapshot returns the files of fsys as one snapshot: each file is a
+// "-- name --" line followed by its content. Binary files are recorded by
+// their checksum.
+func renderSnapshot(t *testing.T, fsys fstest.MapFS) []byte {
+	t.Helper()
+
+	names := make([]string, 0, len(fsys))
+	for name := range fsys {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var b bytes.Buffer
+
+	for _, name := range names {
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bytes.IndexByte(data, 0) >= 0 {
+			fmt.Fprintf(&b, "-- %s (binary, sha256 %x) --\n", name, sha256.Sum256(data))
+			continue
+		}
+
+		content := string(data)
+		for _, n := range snapshotNormalizers {
+			content = n.re.ReplaceAllString(content, n.repl)
+		}
+
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			fmt.Fprintf(&b, "-- %s (no final newline) --\n%s\n", name, content)
+			continue
+		}
+
+		fmt.Fprintf(&b, "-- %s --\n%s", name, content)
+	}
+
+	return b.Bytes()
+}
@@ -0,0 +1,104 @@
+package goinit
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PlaceholderAgeRecipient is written to .sops.yaml when no age key is found.
+const PlaceholderAgeRecipient = "age1replacewithyourpublickey"
+
+type sopsData struct {
+	Name      string
+	Recipient string
+}
+
+// ageKeyFile returns the age key file sops reads by default.
+func ageKeyFile() (string, error) {
+	if path := os.Getenv("SOPS_AGE_KEY_FILE"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "sops", "age", "keys.txt"), nil
+}
+
+// ageRecipient returns the public key of the user's age key.
+func ageRecipient() (string, error) {
+	path, err := ageKeyFile()
+	if err != nil {
+		return "", err
+	}
+
+	out, err := commandOutput("", "age-keygen", "-y", path)
+	if err != nil {
+		return "", fmt.Errorf("error reading age key %s: %w", path, err)
+	}
+
+	// A key file can hold several keys, the first one is used.
+	recipient, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if recipient == "" {
+		return "", errors.New("no age key found in " + path)
+	}
+
+	return recipient, nil
+}
+
+// createSopsSecrets configures sops for the user's age key and encrypts an
+// example secrets file with it. Without sops or a key the example is left
+// decrypted, ready for `make secrets-encrypt`.
+func createSopsSecrets(dir, projectName string) error {
+	data := sopsData{Name: projectName, Recipient: PlaceholderAgeRecipient}
+
+	recipient, err := ageRecipient()
+	if err == nil {
+		data.Recipient = recipient
+	}
+
+	if err := ensureDir(filepath.Join(dir, filepath.Dir(SecretsDecryptedFile))); err != nil {
+		return err
+	}
+
+	filesToRender := []templateFile{
+		{SopsConfigFile, SopsConfigTemplate},
+		{SecretsDecryptedFile, SecretsTemplate},
+	}
+
+	for _, file := range filesToRender {
+		if err := renderFile(dir, file.Name, templatesFS, file.Template, data); err != nil {
+			return fmt.Errorf("error creating %s: %w", file.Name, err)
+		}
+	}
+
+	if err := createFiles(dir, []templateFile{{SecretsDocFile, SecretsDocTemplate}}); err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, SopsMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GitignoreFile, templatesFS, SopsGitignoreTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GitignoreFile, err)
+	}
+
+	if data.Recipient == PlaceholderAgeRecipient {
+		log.Printf("Could not encrypt %s: %v, see %s", SecretsDecryptedFile, err, SecretsDocFile)
+		return nil
+	}
+
+	if err := runCommand(dir, "sops", "--encrypt", "--output", SecretsFile, SecretsDecryptedFile); err != nil {
+		log.Printf("Could not encrypt %s, run `make secrets-encrypt` in the project: %v", SecretsDecryptedFile, err)
+		return nil
+	}
+
+	return os.Remove(filepath.Join(dir, SecretsDecryptedFile))
+}
@@ -0,0 +1,53 @@
+package goinit
+
+import "fmt"
+
+const (
+	SSHAppMainTemplate       = "templates/sshapp/main.go.tmpl"
+	SSHAppTemplate           = "templates/sshapp/sshapp.go.tmpl"
+	SSHAppTestTemplate       = "templates/sshapp/sshapp_internal_test.go.tmpl"
+	SSHAppServiceTemplate    = "templates/sshapp/sshapp.service.tmpl"
+	SSHAppDockerfileTemplate = "templates/sshapp/sshapp.Dockerfile"
+	SSHAppMakefileTemplate   = "templates/sshapp/sshapp.mk.tmpl"
+	SSHAppGitignoreTemplate  = "templates/sshapp/sshapp.gitignore"
+	SSHAppFile               = "internal/sshapp/sshapp.go"
+	SSHAppTestFile           = "internal/sshapp/sshapp_internal_test.go"
+)
+
+// createSSHAppLayout generates an SSH server built on wish, with its host
+// key generated on first start, an example middleware chain and a systemd
+// unit and Dockerfile to deploy it with.
+func createSSHAppLayout(dir string, opts options) error {
+	info := newPackageInfo(dir, opts)
+
+	err := renderFiles(dir, []templateFile{
+		{MainFile, SSHAppMainTemplate},
+		{SSHAppFile, SSHAppTemplate},
+		{SSHAppTestFile, SSHAppTestTemplate},
+		{"deploy/systemd/" + info.Package + ".service", SSHAppServiceTemplate},
+	}, info)
+	if err != nil {
+		return err
+	}
+
+	// -buildx or -docker already created the Dockerfile.
+	if !opts.hasDockerfile() {
+		if err := createDockerfile(dir, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := appendFile(dir, Dockerfile, templatesFS, SSHAppDockerfileTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", Dockerfile, err)
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, SSHAppMakefileTemplate, info); err != nil {
+		return err
+	}
+
+	if err := appendFile(dir, GitignoreFile, templatesFS, SSHAppGitignoreTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", GitignoreFile, err)
+	}
+
+	return nil
+}
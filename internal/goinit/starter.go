@@ -0,0 +1,27 @@
+package goinit
+
+import "path/filepath"
+
+const (
+	StarterMainTemplate      = "templates/starter/main.go.tmpl"
+	StarterHelloTemplate     = "templates/starter/hello.go.tmpl"
+	StarterHelloTestTemplate = "templates/starter/hello_internal_test.go.tmpl"
+	CmdDir                   = "cmd"
+	StarterHelloFile         = "internal/hello/hello.go"
+	StarterHelloTestFile     = "internal/hello/hello_internal_test.go"
+)
+
+// createStarter generates a command in cmd/<project> printing a greeting
+// from an internal package with a test, so a project without a layout
+// builds and tests from the start.
+func createStarter(dir string, ctx projectContext) error {
+	return renderFiles(dir, starterFiles(ctx.ProjectName), ctx)
+}
+
+func starterFiles(projectName string) []templateFile {
+	return []templateFile{
+		{filepath.Join(CmdDir, projectName, MainFile), StarterMainTemplate},
+		{StarterHelloFile, StarterHelloTemplate},
+		{StarterHelloTestFile, StarterHelloTestTemplate},
+	}
+}
@@ -0,0 +1,61 @@
+package goinit
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// printSummary tells what generating opts in dir created: the files, the
+// module path and where the pre-commit hook went, and the commands to run
+// next.
+func printSummary(w io.Writer, dir string, opts options) error {
+	project := filepath.Join(dir, opts.projectName)
+
+	fmt.Fprintf(w, "\nCreated %s (module %s):\n\n", opts.projectName+"/", opts.modulePath())
+
+	if err := writeTree(w, project); err != nil {
+		return err
+	}
+
+	if !opts.skips(ComponentHooks) {
+		fmt.Fprintf(w, "\nThe pre-commit hook %s.\n", hookManagers[opts.hooks].location)
+	}
+
+	fmt.Fprintf(w, "\nNext steps:\n\n  cd %s\n", opts.projectName)
+
+	if runner := opts.taskRunner(); exists(filepath.Join(project, taskRunners[runner].file)) {
+		fmt.Fprintf(w, "  %s setup\n", runner)
+	} else {
+		fmt.Fprintln(w, "  ./scripts/setup.sh")
+	}
+
+	if opts.noGit {
+		return nil
+	}
+
+	if !opts.commit {
+		fmt.Fprintf(w, "  git add -A && git commit -m %q\n", InitialCommitMessage)
+	}
+
+	if !opts.createRemote {
+		remoteURL := "<url>"
+		if repo, err := githubRepo(opts.modulePath()); err == nil {
+			remoteURL = "https://" + GithubHost + repo + ".git"
+		}
+
+		fmt.Fprintf(w, "  git remote add %s %s\n", GitRemote, remoteURL)
+	}
+
+	// Without -commit the branch is the one git init named.
+	if !opts.push {
+		branch := opts.branch
+		if !opts.commit {
+			branch = "HEAD"
+		}
+
+		fmt.Fprintf(w, "  git push -u %s %s\n", GitRemote, branch)
+	}
+
+	return nil
+}
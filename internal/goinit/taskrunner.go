@@ -0,0 +1,150 @@
+package goinit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	TaskRunnerFlag    = "task-runner"
+	TaskRunnerMake    = "make"
+	TaskRunnerTask    = "task"
+	TaskRunnerJust    = "just"
+	TaskfileTemplate  = "templates/taskrunner/Taskfile.yml.tmpl"
+	JustfileTemplate  = "templates/taskrunner/justfile.tmpl"
+	TaskfileFile      = "Taskfile.yml"
+	JustfileFile      = "justfile"
+	ComponentTaskfile = "taskfile"
+	ComponentJustfile = "justfile"
+)
+
+// taskRunner is a -task-runner, which is also the command running its
+// targets: the file it reads them from, the template of the project's, and
+// the suffix replacing .mk in the names of the Make snippets for the
+// snippets adding the same targets to it.
+type taskRunner struct {
+	file     string
+	template string
+	snippet  string
+}
+
+var taskRunners = map[string]taskRunner{
+	TaskRunnerMake: {Makefile, MakefileTemplate, ".mk"},
+	TaskRunnerTask: {TaskfileFile, TaskfileTemplate, ".task.yml"},
+	TaskRunnerJust: {JustfileFile, JustfileTemplate, ".just"},
+}
+
+// taskfileTaskPattern matches the tasks of a Taskfile, indented under its
+// tasks key.
+var taskfileTaskPattern = regexp.MustCompile(`^  ([A-Za-z0-9][A-Za-z0-9_:.-]*):\s*$`)
+
+// taskRunner returns the -task-runner, make when it is not set.
+func (o options) taskRunner() string {
+	if o.runner == "" {
+		return TaskRunnerMake
+	}
+
+	return o.runner
+}
+
+// makeOnlyFlags returns the options given whose targets only exist for
+// Make.
+func (o options) makeOnlyFlags() []string {
+	layoutTargets := o.layout != "" && o.layout != LayoutCronJob && o.layout != LayoutGithubApp &&
+		(o.layout != LayoutBot || o.platform == PlatformDiscord)
+
+	var flags []string
+
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"layout", layoutTargets},
+		{"registry", o.registry != ""},
+		{"debian", o.debian},
+		{"rpm", o.rpm},
+		{"tools", o.tools},
+		{"mocks", o.mocks != ""},
+		{"di", o.di == DIWire},
+		{"sops", o.sops},
+		{"assets", o.assets},
+		{"i18n", o.i18n},
+		{"db", o.db != ""},
+	} {
+		if f.set {
+			flags = append(flags, "-"+f.name)
+		}
+	}
+
+	return flags
+}
+
+// appendTargets adds the targets of the Make snippet name to the file of
+// the runner, from the snippet next to it with the runner's suffix, as
+// grpc.just for grpc.mk. Snippets named .tmpl are rendered with data.
+// Projects without the file, as -skip makefile generates, get none.
+func appendTargets(dir, runner, name string, data any) error {
+	r := taskRunners[runner]
+	if !exists(filepath.Join(dir, r.file)) {
+		return nil
+	}
+
+	name = strings.Replace(name, ".mk", r.snippet, 1)
+
+	var err error
+	if strings.HasSuffix(name, ".tmpl") {
+		err = appendRenderedFile(dir, r.file, templatesFS, name, data)
+	} else {
+		err = appendFile(dir, r.file, templatesFS, name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error updating %s: %w", r.file, err)
+	}
+
+	return nil
+}
+
+// runnerTargets returns the targets of the runner's file in dir, in the
+// order they are defined, leaving out the default one of the Taskfile and
+// justfile, or none when there is no such file.
+func runnerTargets(dir, runner string) []string {
+	name := filepath.Join(dir, taskRunners[runner].file)
+	if runner == TaskRunnerMake {
+		return makeTargets(name)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	pattern := makeTargetPattern
+	if runner == TaskRunnerTask {
+		pattern = taskfileTaskPattern
+	}
+
+	var targets []string
+
+	// The tasks of a Taskfile come after its variables.
+	inTasks := runner != TaskRunnerTask
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "tasks:" {
+			inTasks = true
+		}
+
+		if m := pattern.FindStringSubmatch(line); inTasks && m != nil && m[1] != "default" {
+			targets = append(targets, m[1])
+		}
+	}
+
+	return targets
+}
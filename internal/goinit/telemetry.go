@@ -0,0 +1,263 @@
+package goinit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	ConfigDirName         = "goinit"
+	TelemetrySettingsFile = "telemetry.json"
+	TelemetryUsageFile    = "usage.jsonl"
+	TelemetryTimeout      = 2 * time.Second
+)
+
+// telemetryValues are the flags whose values are recorded: boolean flags
+// and those choosing from a fixed set. The values of the others, such as
+// names, module paths, hosts, -set variables and -brew-tap, could identify
+// the project, so only that they were set is recorded.
+var telemetryValues = map[string]bool{
+	"flags":             true,
+	"release-notes":     true,
+	"release":           true,
+	"registry":          true,
+	ChangelogFlag:       true,
+	"mocks":             true,
+	"di":                true,
+	"layout":            true,
+	"type":              true,
+	"router":            true,
+	DBFlag:              true,
+	MigrationsFlag:      true,
+	ComposeServicesFlag: true,
+	"platform":          true,
+	"framework":         true,
+	GoVersionFlag:       true,
+	"license":           true,
+	CIFlag:              true,
+	LintFlag:            true,
+	EditorFlag:          true,
+	HooksFlag:           true,
+	"skip":              true,
+	TaskRunnerFlag:      true,
+	BuildTargetsFlag:    true,
+}
+
+type telemetrySettings struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// usageEvent is what telemetry records of a generation: the options used,
+// never the project's name or files.
+type usageEvent struct {
+	Date    string            `json:"date"`
+	Version string            `json:"version"`
+	OS      string            `json:"os"`
+	Arch    string            `json:"arch"`
+	Options map[string]string `json:"options"`
+}
+
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating the configuration directory: %w", err)
+	}
+
+	return filepath.Join(dir, ConfigDirName), nil
+}
+
+func loadTelemetrySettings() (telemetrySettings, error) {
+	var settings telemetrySettings
+
+	dir, err := configDir()
+	if err != nil {
+		return settings, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, TelemetrySettingsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return settings, nil
+	}
+
+	if err != nil {
+		return settings, fmt.Errorf("error reading telemetry settings: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("error reading telemetry settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func saveTelemetrySettings(settings telemetrySettings) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating %s: %w", dir, err)
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, TelemetrySettingsFile), append(data, '\n'), 0o644)
+}
+
+// telemetry turns the opt-in usage telemetry on or off, or reports its
+// status.
+func telemetry(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: goinit telemetry on [-endpoint URL] | off | status")
+	}
+
+	settings, err := loadTelemetrySettings()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "on":
+		set := flag.NewFlagSet("telemetry on", flag.ContinueOnError)
+		endpoint := set.String("endpoint", "", "also send usage to this URL as JSON POST requests")
+
+		if err := set.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		if *endpoint != "" {
+			if u, err := url.Parse(*endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("invalid telemetry endpoint %q", *endpoint)
+			}
+		}
+
+		settings = telemetrySettings{Enabled: true, Endpoint: *endpoint}
+	case "off":
+		settings = telemetrySettings{}
+	case "status":
+		return printTelemetryStatus(settings)
+	default:
+		return fmt.Errorf("unknown telemetry command %q, use on, off or status", args[0])
+	}
+
+	if err := saveTelemetrySettings(settings); err != nil {
+		return err
+	}
+
+	return printTelemetryStatus(settings)
+}
+
+func printTelemetryStatus(settings telemetrySettings) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	if !settings.Enabled {
+		fmt.Println("Telemetry is off")
+		return nil
+	}
+
+	fmt.Printf("Telemetry is on, recording the options of each generation to %s\n", filepath.Join(dir, TelemetryUsageFile))
+
+	if settings.Endpoint != "" {
+		fmt.Printf("and sending them to %s\n", settings.Endpoint)
+	}
+
+	return nil
+}
+
+// recordUsage records the options explicitly set on fs when telemetry is
+// on. Telemetry never fails a generation, errors are only logged.
+func recordUsage(fs *flag.FlagSet) {
+	settings, err := loadTelemetrySettings()
+	if err != nil {
+		log.Printf("Could not record usage: %v", err)
+		return
+	}
+
+	if !settings.Enabled {
+		return
+	}
+
+	event := usageEvent{
+		Date:    time.Now().UTC().Format("2006-01-02"),
+		Version: currentVersion(),
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Options: make(map[string]string),
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); (ok && b.IsBoolFlag()) || telemetryValues[f.Name] {
+			event.Options[f.Name] = f.Value.String()
+		} else {
+			event.Options[f.Name] = "set"
+		}
+	})
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Could not record usage: %v", err)
+		return
+	}
+
+	if err := appendUsage(data); err != nil {
+		log.Printf("Could not record usage: %v", err)
+	}
+
+	if settings.Endpoint != "" {
+		if err := sendUsage(settings.Endpoint, data); err != nil {
+			log.Printf("Could not send usage: %v", err)
+		}
+	}
+}
+
+func appendUsage(data []byte) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, TelemetryUsageFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+
+	return err
+}
+
+func sendUsage(endpoint string, data []byte) error {
+	client := &http.Client{Timeout: TelemetryTimeout}
+
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s responded %s", endpoint, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+#####################################
+
+# Publishes the bot's slash commands to Discord.
+register:
+	go run . register
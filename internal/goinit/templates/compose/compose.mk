@@ -0,0 +1,8 @@
+#####################################
+
+# Builds and starts the app and the services of docker-compose.yml.
+up:
+	docker compose up -d --build
+
+down:
+	docker compose down
@@ -0,0 +1,5 @@
+#####################################
+
+# Builds an unsigned .deb from the debian/ directory into the parent folder.
+deb:
+	dpkg-buildpackage -us -uc -b
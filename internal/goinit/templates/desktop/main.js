@@ -0,0 +1,5 @@
+// Methods bound in main.go are available under window.go.<package>.<type>.
+document.getElementById("greet").addEventListener("click", async () => {
+  const name = document.getElementById("name").value;
+  document.getElementById("greeting").textContent = await window.go.main.App.Greet(name);
+});
@@ -0,0 +1,5 @@
+#####################################
+
+# Regenerates internal/app/wire_gen.go from the providers.
+generate::
+	go run github.com/google/wire/cmd/wire@v0.6.0 ./internal/app
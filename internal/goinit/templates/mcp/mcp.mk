@@ -0,0 +1,8 @@
+#####################################
+
+# Opens the MCP Inspector on the server over stdio, needs Node.js.
+inspect: build
+	npx @modelcontextprotocol/inspector $(BIN_DIR)/$(BINARY)
+
+run-sse: build
+	$(BIN_DIR)/$(BINARY) -transport sse
@@ -0,0 +1,28 @@
+#####################################
+
+IMG ?= controller:latest
+CONTROLLER_GEN ?= go run sigs.k8s.io/controller-tools/cmd/controller-gen@v0.16.5
+KUSTOMIZE ?= go run sigs.k8s.io/kustomize/kustomize/v5@v5.5.0
+
+# Regenerates the CRDs and RBAC in config/ from the kubebuilder markers.
+manifests:
+	$(CONTROLLER_GEN) rbac:roleName=manager-role crd paths="./..." output:crd:artifacts:config=config/crd/bases
+
+# Regenerates the DeepCopy methods of the API types.
+generate::
+	$(CONTROLLER_GEN) object paths="./..."
+
+# Installs the CRDs into the cluster of the current kubectl context.
+install: manifests
+	$(KUSTOMIZE) build config/crd | kubectl apply -f -
+
+uninstall: manifests
+	$(KUSTOMIZE) build config/crd | kubectl delete --ignore-not-found -f -
+
+# Deploys the controller built into $(IMG).
+deploy: manifests
+	cd config/manager && $(KUSTOMIZE) edit set image controller=$(IMG)
+	$(KUSTOMIZE) build config/default | kubectl apply -f -
+
+undeploy:
+	$(KUSTOMIZE) build config/default | kubectl delete --ignore-not-found -f -
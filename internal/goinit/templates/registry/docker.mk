@@ -0,0 +1,13 @@
+
+# Internal Docker registry, e.g. artifactory.example.com/docker-local or
+# nexus.example.com:8443. The credentials are read from REGISTRY_USERNAME and
+# REGISTRY_PASSWORD.
+REGISTRY ?= registry.example.com/docker-local
+IMAGE ?= $(REGISTRY)/$(BINARY)
+VERSION ?= $(shell git describe --tags --always --dirty)
+
+docker-login:
+	@echo "$$REGISTRY_PASSWORD" | docker login $(firstword $(subst /, ,$(REGISTRY))) -u "$$REGISTRY_USERNAME" --password-stdin
+
+docker-publish: docker-login
+	docker buildx build --platform linux/amd64,linux/arm64 -t $(IMAGE):$(VERSION) -t $(IMAGE):latest --push .
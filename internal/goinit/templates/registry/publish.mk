@@ -0,0 +1,6 @@
+#####################################
+
+# Publishes the release archives to the internal repository configured in
+# .goreleaser.yml. The credentials are read from the environment.
+publish:
+	goreleaser release --clean
@@ -0,0 +1,15 @@
+#!/bin/bash
+#
+# Builds the frontend into web/static. Uses npm when web/package.json exists,
+# otherwise bundles web/src with esbuild if it is installed. Without either,
+# the committed files in web/static are embedded as they are.
+
+set -e
+
+if [[ -f web/package.json ]]; then
+    (cd web && npm ci && npm run build)
+elif command -v esbuild &> /dev/null; then
+    esbuild web/src/main.js --bundle --minify --outfile=web/static/app.js
+else
+    echo "No web/package.json or esbuild found, using web/static as is."
+fi
@@ -0,0 +1,18 @@
+# Windows counterpart of cibuild.sh.
+
+$ErrorActionPreference = 'Stop'
+
+if (-not (Get-Command go -ErrorAction SilentlyContinue)) {
+    Write-Error 'Go could not be found. Please install it first.'
+    exit 1
+}
+
+function Invoke-Native {
+    & $args[0] $args[1..($args.Length - 1)]
+    if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }
+}
+
+Invoke-Native go mod download
+Invoke-Native go install github.com/golangci/golangci-lint/cmd/golangci-lint@latest
+Invoke-Native go install github.com/segmentio/golines@latest
+Invoke-Native golangci-lint run
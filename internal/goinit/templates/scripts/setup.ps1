@@ -0,0 +1,34 @@
+# Windows counterpart of setup.sh: installs the linters and enables the git
+# hooks, which git runs with the sh bundled with Git for Windows.
+
+$ErrorActionPreference = 'Stop'
+
+function Invoke-Native {
+    & $args[0] $args[1..($args.Length - 1)]
+    if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }
+}
+
+Invoke-Native go mod download
+Invoke-Native go install github.com/golangci/golangci-lint/cmd/golangci-lint@latest
+Invoke-Native go install github.com/segmentio/golines@latest
+
+# Projects generated with -skip hooks have none.
+if (Test-Path .githooks) {
+    Invoke-Native git config core.hooksPath .githooks
+}
+
+# Projects generated with -hooks pre-commit-framework install its hook.
+if (Test-Path .pre-commit-config.yaml) {
+    Invoke-Native pre-commit install
+}
+
+# Projects generated with -type grpc generate their code with buf.
+if (Test-Path buf.yaml) {
+    Invoke-Native go install github.com/bufbuild/buf/cmd/buf@latest
+}
+
+# Projects generated with -hooks lefthook install it and its hooks.
+if (Test-Path lefthook.yml) {
+    Invoke-Native go install github.com/evilmartians/lefthook@latest
+    Invoke-Native lefthook install
+}
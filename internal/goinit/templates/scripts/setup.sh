@@ -0,0 +1,27 @@
+#!/bin/bash
+
+go mod download
+go install github.com/golangci/golangci-lint/cmd/golangci-lint@latest
+go install github.com/segmentio/golines@latest
+
+# Projects generated with -skip hooks have none.
+if [ -d .githooks ]; then
+    git config core.hooksPath .githooks
+fi
+
+# Projects generated with -hooks pre-commit-framework install its hook.
+if [ -f .pre-commit-config.yaml ]; then
+    pre-commit install
+fi
+
+# Projects generated with -type grpc generate their code with buf.
+if [ -f buf.yaml ]; then
+    go install github.com/bufbuild/buf/cmd/buf@latest
+fi
+
+# Projects generated with -hooks lefthook install it and its hooks.
+if [ -f lefthook.yml ]; then
+    go install github.com/evilmartians/lefthook@latest
+    lefthook install
+fi
+
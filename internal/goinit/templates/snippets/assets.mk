@@ -0,0 +1,6 @@
+#####################################
+
+assets:
+	./scripts/assets.sh
+
+build: assets
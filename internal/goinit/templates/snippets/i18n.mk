@@ -0,0 +1,15 @@
+#####################################
+
+GOI18N=go run github.com/nicksnyder/go-i18n/v2/goi18n@latest
+LOCALES_DIR=internal/locale/locales
+
+# Extract messages from the code into active.en.json and create
+# translate.*.json files with the strings that still need translating.
+i18n-extract:
+	$(GOI18N) extract -sourceLanguage en -outdir $(LOCALES_DIR) -format json
+	$(GOI18N) merge -sourceLanguage en -outdir $(LOCALES_DIR) -format json $(LOCALES_DIR)/active.*.json
+
+# Merge the translated translate.*.json files back into active.*.json.
+i18n-merge:
+	$(GOI18N) merge -sourceLanguage en -outdir $(LOCALES_DIR) -format json $(LOCALES_DIR)/active.*.json $(LOCALES_DIR)/translate.*.json
+	rm -f $(LOCALES_DIR)/translate.*.json
@@ -0,0 +1,20 @@
+#####################################
+
+SOPS_FILE ?= secrets/app.enc.yaml
+SOPS_DECRYPTED = $(SOPS_FILE:.enc.yaml=.dec.yaml)
+
+# Opens the encrypted secrets in $EDITOR and encrypts them again on save.
+secrets-edit:
+	sops $(SOPS_FILE)
+
+# Writes the decrypted secrets next to the encrypted file, ignored by git.
+secrets-decrypt:
+	sops --decrypt --output $(SOPS_DECRYPTED) $(SOPS_FILE)
+
+# Encrypts the decrypted secrets back into $(SOPS_FILE).
+secrets-encrypt:
+	sops --encrypt --output $(SOPS_FILE) $(SOPS_DECRYPTED)
+
+# Re-encrypts the data key for the recipients currently in .sops.yaml.
+secrets-updatekeys:
+	sops updatekeys $(SOPS_FILE)
@@ -0,0 +1,10 @@
+#####################################
+
+# Generates the Go code of proto/ into gen/ with buf (https://buf.build),
+# which the server needs to build. Commit gen/ with the protos.
+proto:
+	buf generate
+	go mod tidy
+
+proto-lint:
+	buf lint
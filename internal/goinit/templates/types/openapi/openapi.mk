@@ -0,0 +1,7 @@
+#####################################
+
+# Regenerates internal/api/api.gen.go from the OpenAPI spec in api/ with
+# oapi-codegen, which the server needs to build. Commit it with the spec.
+generate::
+	go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -config oapi-codegen.yaml api/openapi.yaml
+	go mod tidy
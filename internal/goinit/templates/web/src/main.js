@@ -0,0 +1 @@
+document.getElementById("app").textContent = "Hello from the embedded frontend!";
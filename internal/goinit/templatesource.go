@@ -0,0 +1,238 @@
+package goinit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	TemplateFlag = "template"
+	TemplatesDir = "templates"
+)
+
+// overlayFS serves the templates of upper over the embedded ones. upper
+// mirrors the embedded templates directory: a Makefile.tmpl at its root
+// replaces templates/Makefile.tmpl, and templates it does not have are
+// read from lower.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if rel, ok := o.upperName(name); ok {
+		f, err := o.upper.Open(rel)
+		if err == nil {
+			return f, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return o.lower.Open(name)
+}
+
+// ReadDir merges the entries of both, for listing the templates.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(o.lower, name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	rel, ok := o.upperName(name)
+	if !ok {
+		return entries, err
+	}
+
+	upper, upperErr := fs.ReadDir(o.upper, rel)
+	if upperErr != nil {
+		return entries, err
+	}
+
+	merged := make(map[string]fs.DirEntry, len(entries)+len(upper))
+	for _, e := range entries {
+		merged[e.Name()] = e
+	}
+
+	for _, e := range upper {
+		merged[e.Name()] = e
+	}
+
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	all := make([]fs.DirEntry, 0, len(names))
+	for _, name := range names {
+		all = append(all, merged[name])
+	}
+
+	return all, nil
+}
+
+// upperName returns the name of an embedded template in upper.
+func (o overlayFS) upperName(name string) (string, bool) {
+	if name == TemplatesDir {
+		return ".", true
+	}
+
+	rel := strings.TrimPrefix(name, TemplatesDir+"/")
+
+	return rel, rel != name
+}
+
+// templateSource is where the templates over the embedded ones are read
+// from, recorded in the manifest of the projects generated with them.
+type templateSource struct {
+	source string
+	// revision is the commit of a repository's templates.
+	revision string
+}
+
+var templatesSource templateSource
+
+// useTemplates reads the templates from source over the embedded ones
+// until the returned function is called. source is a local directory or a
+// git repository, as a URL or a path like github.com/org/templates, with
+// an optional @ref naming a branch or tag.
+func useTemplates(source string) (func(), error) {
+	return useTemplatesAt(source, "")
+}
+
+// useTemplatesAt is useTemplates with the repository checked out at
+// revision, as recorded when a project was generated, unless it is empty.
+func useTemplatesAt(source, revision string) (func(), error) {
+	if source == "" {
+		return func() {}, nil
+	}
+
+	dir, revision, cleanup, err := resolveTemplates(source, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// Local templates are recorded by their absolute path, which diff and
+	// upgrade read from the project's directory.
+	recorded := source
+	if revision == "" {
+		recorded = dir
+	}
+
+	previous, previousSource := templatesFS, templatesSource
+	templatesFS = overlayFS{upper: os.DirFS(dir), lower: embeddedTemplates}
+	templatesSource = templateSource{source: recorded, revision: revision}
+
+	return func() {
+		templatesFS, templatesSource = previous, previousSource
+		cleanup()
+	}, nil
+}
+
+// resolveTemplates returns the directory of the templates of source and
+// the revision read, cloning it when it is a git repository.
+func resolveTemplates(source, revision string) (string, string, func(), error) {
+	if isLocalTemplates(source) {
+		info, err := os.Stat(source)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("error reading templates: %w", err)
+		}
+
+		if !info.IsDir() {
+			return "", "", nil, fmt.Errorf("templates %s are not a directory", source)
+		}
+
+		// Generation changes directory, so the templates are read from
+		// the absolute path.
+		dir, err := absPath(source)
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		return dir, "", func() {}, nil
+	}
+
+	url, ref := templatesRepository(source)
+
+	tmp, err := os.MkdirTemp("", "goinit-templates-")
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	// A recorded revision need not be the tip of a branch, so the whole
+	// history is cloned to check it out.
+	args := []string{"clone", "--quiet"}
+	if revision == "" {
+		args = append(args, "--depth", "1")
+
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+	}
+
+	// The clone reads the templates rather than changing the project, so
+	// it also runs in a dry run.
+	cmd, run := command(networkTimeout, "git", append(args, url, tmp)...)
+	if err := run(func() error {
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+
+		return nil
+	}); err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("error cloning templates from %s: %w", url, err)
+	}
+
+	if revision != "" {
+		if _, err := commandOutput(tmp, "git", "checkout", "--quiet", "--detach", revision); err != nil {
+			cleanup()
+			return "", "", nil, fmt.Errorf("error checking out revision %s of the templates from %s: %w", revision, url, err)
+		}
+	}
+
+	out, err := commandOutput(tmp, "git", "rev-parse", "HEAD")
+	if err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("error reading the revision of the templates from %s: %w", url, err)
+	}
+
+	return tmp, strings.TrimSpace(string(out)), cleanup, nil
+}
+
+// isLocalTemplates reports whether source names a directory rather than a
+// repository.
+func isLocalTemplates(source string) bool {
+	return exists(source) || filepath.IsAbs(source) || strings.HasPrefix(source, ".")
+}
+
+// templatesRepository returns the git URL and ref of source. Paths without
+// a scheme, like github.com/org/templates@v1, are cloned over HTTPS.
+func templatesRepository(source string) (string, string) {
+	url, ref := source, ""
+
+	// The @ of an SSH URL such as git@github.com:org/t.git precedes the
+	// host, a ref follows the last path element.
+	if i := strings.LastIndex(source, "@"); i > strings.LastIndex(source, "/") && i > 0 {
+		url, ref = source[:i], source[i+1:]
+	}
+
+	if !strings.Contains(url, "://") && !strings.Contains(path.Dir(url), ":") {
+		url = "https://" + url
+	}
+
+	return url, ref
+}
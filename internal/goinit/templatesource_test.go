@@ -0,0 +1,79 @@
+package goinit
+
+import (
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUseTemplatesRepository(t *testing.T) {
+	requireGit(t)
+
+	repo := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	git := func(args ...string) string {
+		t.Helper()
+
+		args = append([]string{"-C", repo, "-c", "user.name=goinit", "-c", "user.email=goinit@example.com"}, args...)
+
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+
+		return strings.TrimSpace(string(out))
+	}
+
+	git("init", "-q")
+	writeTestFile(t, filepath.Join(repo, "Makefile.tmpl"), "first\n")
+	git("add", "Makefile.tmpl")
+	git("commit", "-q", "-m", "first")
+	first := git("rev-parse", "HEAD")
+
+	writeTestFile(t, filepath.Join(repo, "Makefile.tmpl"), "second\n")
+	git("commit", "-q", "-a", "-m", "second")
+	second := git("rev-parse", "HEAD")
+
+	source := "file://" + filepath.ToSlash(repo)
+
+	tests := []struct {
+		revision string
+		want     string
+		recorded string
+	}{
+		{"", "second\n", second},
+		{first, "first\n", first},
+	}
+
+	for _, tt := range tests {
+		restore, err := useTemplatesAt(source, tt.revision)
+		if err != nil {
+			t.Fatalf("useTemplatesAt(%q, %q) = %v", source, tt.revision, err)
+		}
+
+		data, err := fs.ReadFile(templatesFS, TemplatesDir+"/Makefile.tmpl")
+		got := templatesSource
+
+		restore()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != tt.want {
+			t.Errorf("Makefile.tmpl at revision %q = %q, want %q", tt.revision, data, tt.want)
+		}
+
+		if got.source != source || got.revision != tt.recorded {
+			t.Errorf("recorded templates at revision %q = %+v, want %s at %s", tt.revision, got, source, tt.recorded)
+		}
+	}
+
+	if templatesSource != (templateSource{}) {
+		t.Errorf("templates source after restoring = %+v, want none", templatesSource)
+	}
+}
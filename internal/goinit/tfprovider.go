@@ -0,0 +1,100 @@
+package goinit
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	TFProviderMainTemplate      = "templates/tfprovider/main.go.tmpl"
+	TFProviderTemplate          = "templates/tfprovider/provider.go.tmpl"
+	TFProviderTestTemplate      = "templates/tfprovider/provider_internal_test.go.tmpl"
+	TFResourceTemplate          = "templates/tfprovider/example_resource.go.tmpl"
+	TFResourceTestTemplate      = "templates/tfprovider/example_resource_internal_test.go.tmpl"
+	TFDataSourceTemplate        = "templates/tfprovider/example_data_source.go.tmpl"
+	TFDataSourceTestTemplate    = "templates/tfprovider/example_data_source_internal_test.go.tmpl"
+	TFProviderExampleTemplate   = "templates/tfprovider/provider.tf.tmpl"
+	TFResourceExampleTemplate   = "templates/tfprovider/resource.tf.tmpl"
+	TFDataSourceExampleTemplate = "templates/tfprovider/data-source.tf.tmpl"
+	TFProviderMakefileTemplate  = "templates/tfprovider/tfprovider.mk.tmpl"
+	TFGoreleaserTemplate        = "templates/tfprovider/goreleaser.yml"
+	TFReleaserTemplate          = "templates/tfprovider/releaser.yml"
+	TFRegistryManifestTemplate  = "templates/tfprovider/terraform-registry-manifest.json"
+	TFProviderDir               = "internal/provider/"
+	TFExamplesDir               = "examples/"
+	TFRegistryManifestFile      = "terraform-registry-manifest.json"
+	TerraformProviderPrefix     = "terraform-provider-"
+)
+
+var invalidProviderChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// tfProviderData is what the Terraform provider templates are rendered
+// with.
+type tfProviderData struct {
+	ModulePath string
+	// Owner and Provider form the registry address,
+	// registry.terraform.io/<owner>/<provider>.
+	Owner    string
+	Provider string
+	// EnvPrefix prefixes the environment variables the provider reads.
+	EnvPrefix string
+}
+
+func newTFProviderData(opts options) tfProviderData {
+	projectName := opts.projectName
+	module := opts.modulePath()
+	name := strings.TrimPrefix(packageName(projectName), TerraformProviderPrefix)
+	name = invalidProviderChars.ReplaceAllString(name, "")
+
+	return tfProviderData{
+		ModulePath: module,
+		Owner:      strings.ToLower(path.Base(path.Dir(module))),
+		Provider:   name,
+		EnvPrefix:  strings.ToUpper(name),
+	}
+}
+
+// createTFProviderLayout generates a terraform-plugin-framework provider
+// with an example resource and data source, their acceptance tests and the
+// signed GoReleaser release the Terraform registry requires.
+func createTFProviderLayout(dir string, opts options) error {
+	data := newTFProviderData(opts)
+	if data.Provider == "" {
+		return fmt.Errorf("cannot derive a provider name from %q, name the project %s<name>", opts.projectName, TerraformProviderPrefix)
+	}
+
+	err := renderFiles(dir, []templateFile{
+		{MainFile, TFProviderMainTemplate},
+		{TFProviderDir + "provider.go", TFProviderTemplate},
+		{TFProviderDir + "provider_internal_test.go", TFProviderTestTemplate},
+		{TFProviderDir + "example_resource.go", TFResourceTemplate},
+		{TFProviderDir + "example_resource_internal_test.go", TFResourceTestTemplate},
+		{TFProviderDir + "example_data_source.go", TFDataSourceTemplate},
+		{TFProviderDir + "example_data_source_internal_test.go", TFDataSourceTestTemplate},
+		{TFExamplesDir + "provider/provider.tf", TFProviderExampleTemplate},
+		{TFExamplesDir + "resources/" + data.Provider + "_example/resource.tf", TFResourceExampleTemplate},
+		{TFExamplesDir + "data-sources/" + data.Provider + "_example/data-source.tf", TFDataSourceExampleTemplate},
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	// The registry needs signed checksums and the manifest, which replaces
+	// the default release configuration.
+	err = createFiles(dir, []templateFile{
+		{GoreleaserFile, TFGoreleaserTemplate},
+		{ReleaserFile, TFReleaserTemplate},
+		{TFRegistryManifestFile, TFRegistryManifestTemplate},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, TaskRunnerMake, TFProviderMakefileTemplate, data); err != nil {
+		return err
+	}
+
+	return nil
+}
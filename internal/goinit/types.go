@@ -0,0 +1,273 @@
+package goinit
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	TypeCLI                   = "cli"
+	TypeLib                   = "lib"
+	TypeAPI                   = "api"
+	TypeGRPC                  = "grpc"
+	TypeWorker                = "worker"
+	TypeOpenAPI               = "openapi"
+	RouterStdlib              = "stdlib"
+	RouterChi                 = "chi"
+	RouterEcho                = "echo"
+	RouterGin                 = "gin"
+	CLIMainTemplate           = "templates/types/cli/main.go.tmpl"
+	CLIRootTemplate           = "templates/types/cli/root.go.tmpl"
+	CLIHelloTemplate          = "templates/types/cli/hello.go.tmpl"
+	CLIHelloTestTemplate      = "templates/types/cli/hello_internal_test.go.tmpl"
+	CLIVersionTemplate        = "templates/types/cli/version.go.tmpl"
+	CLIVersionTestTemplate    = "templates/types/cli/version_internal_test.go.tmpl"
+	LibTemplate               = "templates/types/lib/lib.go.tmpl"
+	LibTestTemplate           = "templates/types/lib/lib_internal_test.go.tmpl"
+	LibDocTemplate            = "templates/types/lib/doc.go.tmpl"
+	LibExampleTemplate        = "templates/types/lib/example.go.tmpl"
+	LibExampleTestTemplate    = "templates/types/lib/example_test.go.tmpl"
+	APIMainTemplate           = "templates/types/api/main.go.tmpl"
+	APIHandlerTemplate        = "templates/types/api/handler.go.tmpl"
+	APIHandlerTestTemplate    = "templates/types/api/handler_internal_test.go.tmpl"
+	APIMiddlewareTemplate     = "templates/types/api/middleware.go.tmpl"
+	APIDockerfileTemplate     = "templates/types/api/api.Dockerfile"
+	APIMakefileTemplate       = "templates/types/api/api.mk.tmpl"
+	GRPCMainTemplate          = "templates/types/grpc/main.go.tmpl"
+	GRPCServerTemplate        = "templates/types/grpc/server.go.tmpl"
+	GRPCServerTestTemplate    = "templates/types/grpc/server_internal_test.go.tmpl"
+	GRPCGreeterTemplate       = "templates/types/grpc/greeter.go.tmpl"
+	GRPCMakefileTemplate      = "templates/types/grpc/grpc.mk"
+	GRPCProtoTemplate         = "templates/types/grpc/service.proto.tmpl"
+	BufConfigTemplate         = "templates/types/grpc/buf.yaml"
+	BufGenerateTemplate       = "templates/types/grpc/buf.gen.yaml.tmpl"
+	OpenAPISpecTemplate       = "templates/types/openapi/openapi.yaml.tmpl"
+	OapiCodegenConfigTemplate = "templates/types/openapi/oapi-codegen.yaml"
+	OpenAPIServerTemplate     = "templates/types/openapi/server.go.tmpl"
+	OpenAPIServerTestTemplate = "templates/types/openapi/server_internal_test.go.tmpl"
+	OpenAPIMakefileTemplate   = "templates/types/openapi/openapi.mk"
+	WorkerMainTemplate        = "templates/types/worker/main.go.tmpl"
+	WorkerTemplate            = "templates/types/worker/worker.go.tmpl"
+	WorkerTestTemplate        = "templates/types/worker/worker_internal_test.go.tmpl"
+	WorkerServiceTemplate     = "templates/types/worker/worker.service.tmpl"
+	WorkerPostinstallTemplate = "templates/types/worker/postinstall.sh.tmpl"
+	WorkerPreremoveTemplate   = "templates/types/worker/preremove.sh.tmpl"
+	CLIRootFile               = "internal/cli/root.go"
+	CLIHelloFile              = "internal/cli/hello.go"
+	CLIHelloTestFile          = "internal/cli/hello_internal_test.go"
+	CLIVersionFile            = "internal/cli/version.go"
+	CLIVersionTestFile        = "internal/cli/version_internal_test.go"
+	APIHandlerFile            = "internal/api/handler.go"
+	APIHandlerTestFile        = "internal/api/handler_internal_test.go"
+	APIMiddlewareFile         = "internal/api/middleware.go"
+	GRPCServerFile            = "internal/server/server.go"
+	GRPCServerTestFile        = "internal/server/server_internal_test.go"
+	GRPCGreeterFile           = "internal/server/greeter.go"
+	LibDocFile                = "doc.go"
+	LibExampleFile            = "examples/greeting/main.go"
+	LibExampleTestFile        = "example_test.go"
+	OpenAPISpecFile           = "api/openapi.yaml"
+	OapiCodegenConfigFile     = "oapi-codegen.yaml"
+	OpenAPIServerFile         = "internal/api/server.go"
+	OpenAPIServerTestFile     = "internal/api/server_internal_test.go"
+	OapiCodegen               = "github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1"
+	WorkerFile                = "internal/worker/worker.go"
+	WorkerTestFile            = "internal/worker/worker_internal_test.go"
+	SystemdDir                = "deploy/systemd"
+	ProtoDir                  = "proto"
+	BufConfigFile             = "buf.yaml"
+	BufGenerateFile           = "buf.gen.yaml"
+	DefaultGoPackageName      = "app"
+)
+
+// projectTypes maps the supported -type values to the function generating
+// the archetype. All but lib put their command in cmd/<project>.
+var projectTypes = map[string]func(dir string, ctx projectContext) error{
+	TypeCLI:     createCLIType,
+	TypeLib:     createLibType,
+	TypeAPI:     createAPIType,
+	TypeGRPC:    createGRPCType,
+	TypeWorker:  createWorkerType,
+	TypeOpenAPI: createOpenAPIType,
+}
+
+// apiRouters maps the -router values to the handler template of the api
+// archetype. The stdlib one brings its own middleware.
+var apiRouters = map[string]string{
+	RouterStdlib: APIHandlerTemplate,
+	RouterChi:    "templates/types/api/chi.handler.go.tmpl",
+	RouterEcho:   "templates/types/api/echo.handler.go.tmpl",
+	RouterGin:    "templates/types/api/gin.handler.go.tmpl",
+}
+
+func cmdMainFile(ctx projectContext) string {
+	return filepath.Join(CmdDir, ctx.ProjectName, MainFile)
+}
+
+// createCLIType generates a cobra command line tool with an example
+// subcommand, and a version subcommand printing what the builds set.
+func createCLIType(dir string, ctx projectContext) error {
+	return renderFiles(dir, []templateFile{
+		{cmdMainFile(ctx), CLIMainTemplate},
+		{CLIRootFile, CLIRootTemplate},
+		{CLIHelloFile, CLIHelloTemplate},
+		{CLIHelloTestFile, CLIHelloTestTemplate},
+		{CLIVersionFile, CLIVersionTemplate},
+		{CLIVersionTestFile, CLIVersionTestTemplate},
+	}, ctx)
+}
+
+// createLibType generates a library package in the module's root, with its
+// package comment in doc.go, a testable example and an example program.
+func createLibType(dir string, ctx projectContext) error {
+	return renderFiles(dir, []templateFile{
+		{LibDocFile, LibDocTemplate},
+		{ctx.PackageName + ".go", LibTemplate},
+		{ctx.PackageName + "_internal_test.go", LibTestTemplate},
+		{LibExampleTestFile, LibExampleTestTemplate},
+		{LibExampleFile, LibExampleTemplate},
+	}, ctx)
+}
+
+// createAPIType generates an HTTP server on the -router with a health
+// endpoint, request logging and panic recovery, and graceful shutdown.
+func createAPIType(dir string, ctx projectContext) error {
+	files := []templateFile{
+		{cmdMainFile(ctx), APIMainTemplate},
+		{APIHandlerFile, apiRouters[ctx.Router]},
+		{APIHandlerTestFile, APIHandlerTestTemplate},
+	}
+
+	if ctx.Router == RouterStdlib {
+		files = append(files, templateFile{APIMiddlewareFile, APIMiddlewareTemplate})
+	}
+
+	return renderFiles(dir, files, ctx)
+}
+
+// createAPIContainer makes the Dockerfile, unless -docker or -buildx did,
+// serve the API on its port, and adds the Make variable of its address and
+// a target running its image.
+func createAPIContainer(dir string, opts options) error {
+	if !opts.hasDockerfile() {
+		if err := createDockerfile(dir, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := appendFile(dir, Dockerfile, templatesFS, APIDockerfileTemplate); err != nil {
+		return fmt.Errorf("error updating %s: %w", Dockerfile, err)
+	}
+
+	return appendTargets(dir, opts.taskRunner(), APIMakefileTemplate, newProjectContext(dir, opts))
+}
+
+// createGRPCType generates a gRPC server implementing an example service
+// defined in proto/, with the health and reflection services and tests
+// calling them, the buf configuration generating the service's code and a
+// proto Make target running it. The code is generated right away when buf
+// is installed, as the server does not build without it.
+func createGRPCType(dir string, ctx projectContext) error {
+	if err := createFiles(dir, []templateFile{{BufConfigFile, BufConfigTemplate}}); err != nil {
+		return err
+	}
+
+	err := renderFiles(dir, []templateFile{
+		{cmdMainFile(ctx), GRPCMainTemplate},
+		{GRPCServerFile, GRPCServerTemplate},
+		{GRPCGreeterFile, GRPCGreeterTemplate},
+		{GRPCServerTestFile, GRPCServerTestTemplate},
+		{filepath.Join(ProtoDir, ctx.PackageName, "v1", ctx.PackageName+".proto"), GRPCProtoTemplate},
+		{BufGenerateFile, BufGenerateTemplate},
+	}, ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, ctx.TaskRunner, GRPCMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	if _, err := commandOutput("", "buf", "--version"); err != nil {
+		log.Print("buf is not installed, run `make proto` once it is to generate the code the server needs")
+		return nil
+	}
+
+	if err := runNetworkCommand(dir, "buf", "generate"); err != nil {
+		log.Printf("Could not generate the code of %s, run `make proto`: %v", ProtoDir, err)
+	}
+
+	return nil
+}
+
+// createOpenAPIType generates an HTTP server implementing the operations of
+// an OpenAPI spec in api/, with the oapi-codegen configuration generating
+// its handler interface, routes and models into internal/api and a generate
+// Make target running it. The code is generated right away, as the server
+// does not build without it.
+func createOpenAPIType(dir string, ctx projectContext) error {
+	if err := createFiles(dir, []templateFile{{OapiCodegenConfigFile, OapiCodegenConfigTemplate}}); err != nil {
+		return err
+	}
+
+	err := renderFiles(dir, []templateFile{
+		{cmdMainFile(ctx), APIMainTemplate},
+		{OpenAPISpecFile, OpenAPISpecTemplate},
+		{OpenAPIServerFile, OpenAPIServerTemplate},
+		{OpenAPIServerTestFile, OpenAPIServerTestTemplate},
+		{APIMiddlewareFile, APIMiddlewareTemplate},
+	}, ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := appendTargets(dir, ctx.TaskRunner, OpenAPIMakefileTemplate, nil); err != nil {
+		return err
+	}
+
+	if err := runNetworkCommand(dir, "go", "run", OapiCodegen, "-config", OapiCodegenConfigFile, OpenAPISpecFile); err != nil {
+		log.Printf("Could not generate the code of %s, run `%s generate`: %v", OpenAPISpecFile, ctx.TaskRunner, err)
+	}
+
+	return nil
+}
+
+// createWorkerType generates a long-running worker processing work on an
+// interval until it is stopped, and its systemd unit, which the .deb and
+// .rpm packages of the GoReleaser configuration install and enable.
+func createWorkerType(dir string, ctx projectContext) error {
+	return renderFiles(dir, []templateFile{
+		{cmdMainFile(ctx), WorkerMainTemplate},
+		{WorkerFile, WorkerTemplate},
+		{WorkerTestFile, WorkerTestTemplate},
+		{filepath.Join(SystemdDir, ctx.ProjectName+".service"), WorkerServiceTemplate},
+		{filepath.Join(SystemdDir, "postinstall.sh"), WorkerPostinstallTemplate},
+		{filepath.Join(SystemdDir, "preremove.sh"), WorkerPreremoveTemplate},
+	}, ctx)
+}
+
+// goPackageName turns a project name into a Go package name: lowercase
+// letters and digits, not starting with a digit nor a keyword.
+func goPackageName(name string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		if 'a' <= r && r <= 'z' || '0' <= r && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	pkg := strings.TrimLeft(b.String(), "0123456789")
+
+	switch {
+	case pkg == "":
+		return DefaultGoPackageName
+	case token.IsKeyword(pkg):
+		return pkg + "pkg"
+	}
+
+	return pkg
+}
@@ -0,0 +1,454 @@
+package goinit
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	KeyUp       = "up"
+	KeyDown     = "down"
+	KeyLeft     = "left"
+	KeyRight    = "right"
+	KeySpace    = "space"
+	KeyEnter    = "enter"
+	KeyPreview  = "p"
+	KeyQuit     = "q"
+	DefaultRows = 24
+)
+
+// uiItem is a line of the terminal UI: a checkbox, or a choice cycling
+// through values. It sets the flag named flag, or leaves out component when
+// it is unchecked.
+type uiItem struct {
+	label     string
+	flag      string
+	component string
+	values    []string
+	value     int
+	checked   bool
+}
+
+func (it uiItem) current() string {
+	return it.values[it.value]
+}
+
+// uiAction is what the UI does once a key is handled.
+type uiAction int
+
+const (
+	uiRedraw uiAction = iota
+	uiGenerate
+	uiQuit
+)
+
+// uiModel is the state of the terminal UI. As in bubbletea, keys update it
+// and the screen is drawn from it, while the loop in runUI does the work
+// with side effects: previewing and generating.
+type uiModel struct {
+	name   string
+	module string
+	// given are the other flags the UI was started with, which the
+	// choices are generated with.
+	given   []string
+	items   []uiItem
+	cursor  int
+	preview bool
+	// tree lists the files of the current choices, and is empty when they
+	// changed since it was generated.
+	tree   string
+	status string
+}
+
+// newUIModel starts the UI on the values of set, which hold the defaults
+// and the user configuration.
+func newUIModel(set *flag.FlagSet, opts options) uiModel {
+	items := []uiItem{
+		{label: "Task runner file", component: ComponentMakefile},
+		{label: "Task runner", flag: TaskRunnerFlag, values: []string{TaskRunnerMake, TaskRunnerTask, TaskRunnerJust}},
+		{label: "CI workflows", component: ComponentCI},
+		{label: "Pre-commit hook", component: ComponentHooks},
+		{label: "Hook manager", flag: HooksFlag, values: []string{HooksScript, HooksPreCommit, HooksLefthook}},
+		{label: "Dockerfile", flag: "docker"},
+		{label: "Dev container", flag: "devcontainer"},
+		{label: "Editor settings", flag: EditorFlag, values: []string{EditorNone, EditorVSCode}},
+		{label: "License", flag: "license", values: []string{"", "mit", "apache-2.0", "bsd-3-clause"}},
+		{label: "Archetype", flag: "type", values: []string{"", TypeCLI, TypeLib, TypeAPI, TypeGRPC, TypeWorker, TypeOpenAPI}},
+	}
+
+	for i, it := range items {
+		switch {
+		case it.component != "":
+			items[i].checked = !opts.skips(it.component)
+		case it.values == nil:
+			items[i].checked = set.Lookup(it.flag).Value.String() == "true"
+		default:
+			value := set.Lookup(it.flag).Value.String()
+			for j, v := range it.values {
+				if v == value {
+					items[i].value = j
+				}
+			}
+		}
+	}
+
+	return uiModel{name: opts.projectName, module: opts.modulePath(), given: changedArgs(set), items: items}
+}
+
+// update applies key to the model.
+func (m *uiModel) update(key string) uiAction {
+	it := &m.items[m.cursor]
+	m.status = ""
+
+	switch key {
+	case KeyUp, "k":
+		m.cursor = (m.cursor + len(m.items) - 1) % len(m.items)
+	case KeyDown, "j":
+		m.cursor = (m.cursor + 1) % len(m.items)
+	case KeySpace, KeyRight, "l":
+		if it.values == nil {
+			it.checked = !it.checked
+		} else {
+			it.value = (it.value + 1) % len(it.values)
+		}
+
+		m.tree = ""
+	case KeyLeft, "h":
+		if it.values == nil {
+			it.checked = !it.checked
+		} else {
+			it.value = (it.value + len(it.values) - 1) % len(it.values)
+		}
+
+		m.tree = ""
+	case KeyPreview:
+		m.preview = !m.preview
+	case KeyEnter:
+		return uiGenerate
+	case KeyQuit, "ctrl-c", "esc":
+		return uiQuit
+	}
+
+	return uiRedraw
+}
+
+// args returns the flags of the choices, after the others given.
+func (m uiModel) args() []string {
+	// The choices come after the given flags, overriding them.
+	args := append([]string{"-" + ProjectNameFlag + "=" + m.name}, m.given...)
+
+	var skip []string
+
+	for _, it := range m.items {
+		switch {
+		case it.component != "":
+			if !it.checked {
+				skip = append(skip, it.component)
+			}
+		case it.values == nil:
+			args = append(args, "-"+it.flag+"="+strconv.FormatBool(it.checked))
+		default:
+			args = append(args, "-"+it.flag+"="+it.current())
+		}
+	}
+
+	return append(args, "-skip="+strings.Join(skip, ","))
+}
+
+// view draws the model on a screen of rows lines, cutting the preview.
+func (m uiModel) view(rows int) string {
+	lines := []string{"goinit: " + m.name + " (" + m.module + ")", ""}
+
+	for i, it := range m.items {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+
+		switch {
+		case it.values == nil && it.checked:
+			lines = append(lines, cursor+"[x] "+it.label)
+		case it.values == nil:
+			lines = append(lines, cursor+"[ ] "+it.label)
+		default:
+			value := it.current()
+			if value == "" {
+				value = "none"
+			}
+
+			lines = append(lines, cursor+"    "+it.label+": < "+value+" >")
+		}
+	}
+
+	lines = append(lines, "", "up/down move, space/left/right change, p preview, enter generate, q quit")
+
+	if m.status != "" {
+		lines = append(lines, m.status)
+	}
+
+	if !m.preview || m.tree == "" {
+		return strings.Join(lines, "\r\n")
+	}
+
+	lines = append(lines, "", m.name+"/")
+	tree := strings.Split(strings.TrimSuffix(m.tree, "\n"), "\n")
+
+	if room := rows - len(lines) - 1; len(tree) > room && room > 0 {
+		tree = append(tree[:room], fmt.Sprintf("  ... %d more", len(tree)-room))
+	}
+
+	return strings.Join(append(lines, tree...), "\r\n")
+}
+
+// errNoRawMode is returned by runUI when the terminal cannot be switched
+// to raw mode, as on Windows, whose console has no stty.
+var errNoRawMode = errors.New("the terminal cannot be switched to raw mode")
+
+// ui runs the terminal UI choosing the components of the project named by
+// the argument, and generates it in the working directory.
+func ui(args []string) error {
+	var opts options
+
+	set := flag.NewFlagSet("ui", flag.ContinueOnError)
+	registerFlags(set, &opts)
+
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "usage: goinit ui [flags] [name]\n")
+	}
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	if set.NArg() > 1 {
+		return fmt.Errorf("unexpected arguments %q, give the project name once", set.Args())
+	}
+
+	if set.NArg() == 1 {
+		if err := set.Set(ProjectNameFlag, set.Arg(0)); err != nil {
+			return err
+		}
+	}
+
+	if err := applyUserConfig(set); err != nil {
+		return err
+	}
+
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return errors.New("the terminal UI needs a terminal, use goinit new -i to answer questions instead")
+	}
+
+	model := newUIModel(set, opts)
+
+	generate, err := runUI(&model)
+
+	switch {
+	case errors.Is(err, errNoRawMode):
+		fmt.Fprintf(os.Stderr, "%v, asking the questions of goinit new -i instead\n", err)
+
+		if err := runWizard(os.Stdin, os.Stderr, set, &opts); err != nil {
+			return err
+		}
+
+		opts.args = changedArgs(set)
+
+		if err := opts.validate(); err != nil {
+			return err
+		}
+	case err != nil || !generate:
+		return err
+	default:
+		if opts, set, err = model.options(); err != nil {
+			return err
+		}
+	}
+
+	steps = newTerminalProgress(false)
+	log.SetOutput(steps)
+
+	defer handleInterrupts()()
+
+	if err := generateProject(".", opts); err != nil {
+		steps.fail()
+		return fmt.Errorf("error creating project: %w", err)
+	}
+
+	logs.summary(".", opts)
+	recordUsage(set)
+
+	return nil
+}
+
+// options parses and validates the flags of the choices.
+func (m uiModel) options() (options, *flag.FlagSet, error) {
+	var opts options
+
+	set := flag.NewFlagSet("ui", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	registerFlags(set, &opts)
+
+	if err := set.Parse(m.args()); err != nil {
+		return opts, set, err
+	}
+
+	if err := applyUserConfig(set); err != nil {
+		return opts, set, err
+	}
+
+	opts.args = changedArgs(set)
+
+	return opts, set, opts.validate()
+}
+
+// runUI puts the terminal in raw mode and handles keys until the choices
+// are generated or the UI is quit, and restores the terminal.
+func runUI(m *uiModel) (bool, error) {
+	if runtime.GOOS == "windows" {
+		return false, errNoRawMode
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return false, fmt.Errorf("%w, reading its settings with stty failed: %v", errNoRawMode, err)
+	}
+
+	if _, err := stty("raw", "-echo"); err != nil {
+		return false, fmt.Errorf("%w: %v", errNoRawMode, err)
+	}
+
+	// Generating the preview logs its steps, which would draw over the UI.
+	log.SetOutput(io.Discard)
+
+	fmt.Print("\x1b[?25l")
+
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[H\x1b[2J")
+
+		if _, err := stty(saved); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not restore the terminal, run `stty sane`: %v\n", err)
+		}
+	}()
+
+	in := bufio.NewReader(os.Stdin)
+
+	for {
+		if m.preview && m.tree == "" {
+			m.tree, m.status = previewTree(*m)
+		}
+
+		fmt.Print("\x1b[H\x1b[2J" + m.view(terminalRows()))
+
+		key, err := readKey(in)
+		if err != nil {
+			return false, fmt.Errorf("error reading the terminal: %w", err)
+		}
+
+		switch m.update(key) {
+		case uiQuit:
+			return false, nil
+		case uiGenerate:
+			if _, _, err := m.options(); err != nil {
+				m.status = "Cannot generate: " + err.Error()
+				continue
+			}
+
+			return true, nil
+		case uiRedraw:
+		}
+	}
+}
+
+// previewTree lists the files the choices of m generate, or returns why
+// they cannot be generated.
+func previewTree(m uiModel) (string, string) {
+	opts, _, err := m.options()
+	if err != nil {
+		return "", "Cannot preview: " + err.Error()
+	}
+
+	var tree bytes.Buffer
+
+	if _, err := planProject(&tree, opts); err != nil {
+		return "", "Cannot preview: " + err.Error()
+	}
+
+	return tree.String(), ""
+}
+
+// readKey reads a key press in raw mode, decoding the escape sequences of
+// the arrow keys.
+func readKey(in *bufio.Reader) (string, error) {
+	b, err := in.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	switch b {
+	case 3:
+		return "ctrl-c", nil
+	case '\r', '\n':
+		return KeyEnter, nil
+	case ' ':
+		return KeySpace, nil
+	case 0x1b:
+		if in.Buffered() < 2 {
+			return "esc", nil
+		}
+
+		seq := make([]byte, 2)
+		if _, err := io.ReadFull(in, seq); err != nil {
+			return "", err
+		}
+
+		switch string(seq) {
+		case "[A":
+			return KeyUp, nil
+		case "[B":
+			return KeyDown, nil
+		case "[C":
+			return KeyRight, nil
+		case "[D":
+			return KeyLeft, nil
+		}
+
+		return "", nil
+	}
+
+	return string(b), nil
+}
+
+// stty runs stty on the terminal of stdin.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+
+	out, err := cmd.Output()
+
+	return strings.TrimSpace(string(out)), err
+}
+
+// terminalRows returns the height of the terminal, or DefaultRows when it
+// cannot be read.
+func terminalRows() int {
+	out, err := stty("size")
+	if err != nil {
+		return DefaultRows
+	}
+
+	rows, _, _ := strings.Cut(out, " ")
+	if n, err := strconv.Atoi(rows); err == nil && n > 0 {
+		return n
+	}
+
+	return DefaultRows
+}
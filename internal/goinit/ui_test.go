@@ -0,0 +1,44 @@
+package goinit
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestUIModelOptions(t *testing.T) {
+	isolateSnapshotEnv(t)
+
+	var opts options
+
+	set := flag.NewFlagSet("ui", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	registerFlags(set, &opts)
+
+	if err := set.Parse([]string{"-module=example.com/team/x", "-ci=gitlab", "-license=mit", "foo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.projectName = set.Arg(0)
+
+	model := newUIModel(set, opts)
+	if model.module != "example.com/team/x" {
+		t.Errorf("module shown = %q, want the given example.com/team/x", model.module)
+	}
+
+	// The license chosen in the UI overrides the given one.
+	for i, it := range model.items {
+		if it.flag == "license" {
+			model.items[i].value = 0
+		}
+	}
+
+	got, _, err := model.options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.projectName != "foo" || got.module != "example.com/team/x" || got.ci != "gitlab" || got.license != "" {
+		t.Errorf("options = name %q, module %q, ci %q, license %q, want foo, example.com/team/x, gitlab and none", got.projectName, got.module, got.ci, got.license)
+	}
+}
@@ -0,0 +1,193 @@
+package goinit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// undo removes what goinit created in the project in the working directory,
+// as recorded in its manifest. Files changed since are kept unless -force.
+func undo(args []string) error {
+	set := flag.NewFlagSet("undo", flag.ContinueOnError)
+	force := set.Bool("force", false, "also remove created files that were changed since")
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	manifest, err := readManifest(ManifestFile)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(manifest.Files))
+	for path := range manifest.Files {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	// Every path is checked before anything is removed, so a manifest
+	// naming files outside the project removes nothing.
+	for _, path := range paths {
+		if err := checkManifestPath(path); err != nil {
+			return err
+		}
+	}
+
+	kept := make(map[string]string)
+
+	for _, path := range paths {
+		sum, err := fileChecksum(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		if sum != manifest.Files[path] && !*force {
+			fmt.Printf("Kept %s, changed since it was generated\n", path)
+			kept[path] = manifest.Files[path]
+
+			continue
+		}
+
+		if err := os.Remove(filepath.FromSlash(path)); err != nil {
+			return fmt.Errorf("error removing %s: %w", path, err)
+		}
+
+		removeEmptyDirs(filepath.Dir(filepath.FromSlash(path)))
+	}
+
+	if exists(".git") {
+		for _, key := range manifest.GitConfig {
+			// Exit status 5 means the key is not set, which is fine.
+			err := runCommand("", "git", "config", "--unset-all", key)
+
+			var exitErr *exec.ExitError
+			if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 5) {
+				return fmt.Errorf("error unsetting git config %s: %w", key, err)
+			}
+		}
+	}
+
+	// The kept files stay recorded, so undo -force can still remove them.
+	if len(kept) > 0 {
+		manifest.Files, manifest.GitConfig = kept, nil
+		if err := writeManifest(ManifestFile, manifest); err != nil {
+			return fmt.Errorf("error updating %s: %w", ManifestFile, err)
+		}
+
+		fmt.Printf("Removed the generated files, %d changed files were kept (use -force to remove them)\n", len(kept))
+
+		return nil
+	}
+
+	if err := os.Remove(ManifestFile); err != nil {
+		return fmt.Errorf("error removing %s: %w", ManifestFile, err)
+	}
+
+	return removeRepository(manifest)
+}
+
+// checkManifestPath checks that path, as recorded in the manifest, names a
+// file inside the project in the working directory: it is relative, does
+// not climb out with .. and its directory does not link outside.
+func checkManifestPath(path string) error {
+	name := filepath.FromSlash(path)
+
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" || strings.HasPrefix(path, "/") {
+		return fmt.Errorf("%s records the absolute path %s, not a file of the project", ManifestFile, path)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current working directory: %w", err)
+	}
+
+	root, err := filepath.EvalSymlinks(wd)
+	if err != nil {
+		return fmt.Errorf("error resolving the project directory: %w", err)
+	}
+
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, filepath.Dir(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		// Without its directory there is nothing to remove, but the path
+		// still has to stay inside the project.
+		dir, err = filepath.Join(root, filepath.Dir(name)), nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error resolving %s: %w", path, err)
+	}
+
+	rel, err := filepath.Rel(root, filepath.Join(dir, filepath.Base(name)))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s records %s, which is outside the project", ManifestFile, path)
+	}
+
+	return nil
+}
+
+// removeRepository removes the repository goinit initialized, and the
+// project directory with it, when nothing was committed or added since.
+func removeRepository(manifest projectManifest) error {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		return fmt.Errorf("error reading the project directory: %w", err)
+	}
+
+	if !manifest.CreatedRepository || len(entries) != 1 || entries[0].Name() != ".git" {
+		fmt.Println("Removed the generated files")
+		return nil
+	}
+
+	if runCommand("", "git", "rev-parse", "--verify", "-q", "HEAD") == nil {
+		fmt.Println("Removed the generated files, the repository has commits so it was kept")
+		return nil
+	}
+
+	if err := os.RemoveAll(".git"); err != nil {
+		return fmt.Errorf("error removing the repository: %w", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current working directory: %w", err)
+	}
+
+	if filepath.Base(wd) == manifest.Name {
+		if err := os.Chdir(".."); err != nil {
+			return fmt.Errorf("error leaving the project directory: %w", err)
+		}
+
+		if err := os.Remove(wd); err != nil {
+			return fmt.Errorf("error removing %s: %w", wd, err)
+		}
+	}
+
+	fmt.Printf("Removed %s\n", wd)
+
+	return nil
+}
+
+// removeEmptyDirs removes dir and its parents up to the working directory
+// while they are empty.
+func removeEmptyDirs(dir string) {
+	for dir != "." && !strings.HasPrefix(dir, "..") {
+		if os.Remove(dir) != nil {
+			return
+		}
+
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,67 @@
+package goinit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckManifestPath(t *testing.T) {
+	outside := t.TempDir()
+	project := t.TempDir()
+	chdir(t, project)
+
+	if err := os.MkdirAll("internal/api", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(outside, "linked"); err != nil {
+		t.Skipf("cannot create symlinks: %v", err)
+	}
+
+	for _, path := range []string{"go.mod", "internal/api/api.go", "missing/dir/file.go", "internal/../go.mod"} {
+		if err := checkManifestPath(path); err != nil {
+			t.Errorf("checkManifestPath(%q) = %v, want nil", path, err)
+		}
+	}
+
+	for _, path := range []string{
+		"/etc/passwd",
+		filepath.ToSlash(filepath.Join(outside, "file")),
+		"../file",
+		"internal/../../file",
+		"internal/..",
+		"linked/file",
+	} {
+		if err := checkManifestPath(path); err == nil {
+			t.Errorf("checkManifestPath(%q) = nil, want an error", path)
+		}
+	}
+}
+
+func TestUndoRejectsPathsOutsideTheProject(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "keep.txt")
+	writeTestFile(t, outside, "outside\n")
+
+	chdir(t, t.TempDir())
+	writeTestFile(t, "go.mod", "module example.com/payments\n")
+
+	m := projectManifest{Name: "payments", Files: map[string]string{
+		"go.mod":                  checksum([]byte("module example.com/payments\n")),
+		filepath.ToSlash(outside): checksum([]byte("outside\n")),
+	}}
+
+	if err := writeManifest(ManifestFile, m); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := undo([]string{"-force"}); err == nil {
+		t.Fatal("undo = nil, want an error for the path outside the project")
+	}
+
+	for _, name := range []string{outside, "go.mod", ManifestFile} {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("undo removed %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package goinit
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const RejectExt = ".rej"
+
+// upgradeOwned are the files the go command owns, which the templates only
+// seed.
+var upgradeOwned = map[string]bool{
+	"go.mod": true,
+	"go.sum": true,
+}
+
+// upgrade updates the files goinit generated to what the current templates
+// generate from the recorded options. Files changed in the project since
+// are merged with git merge-file against the version goinit generated,
+// found in the repository's history, and otherwise get the template's
+// changes as a diff in a .rej file next to them.
+func upgrade(args []string) error {
+	set := flag.NewFlagSet("upgrade", flag.ContinueOnError)
+	dryRun := set.Bool("dry-run", false, "list what would be updated without changing the project")
+
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	if !exists(".git") {
+		return errors.New("run goinit upgrade from the root of the project's repository")
+	}
+
+	manifest, err := readManifest(ManifestFile)
+	if err != nil {
+		return err
+	}
+
+	opts, err := manifest.options()
+	if err != nil {
+		return err
+	}
+
+	// Generating for comparison must not change the GitHub repository, nor
+	// commit.
+	opts.labels, opts.protect, opts.commit, opts.createRemote = false, false, false, false
+
+	restore, err := useTemplatesAt(manifest.Template, manifest.TemplateRevision)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	tmp, err := os.MkdirTemp("", "goinit-upgrade-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := generateIn(rootCtx, tmp, opts); err != nil {
+		return err
+	}
+
+	generated := filepath.Join(tmp, opts.projectName)
+
+	names, err := walkFiles(generated)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(names))
+	for name := range names {
+		if !upgradeOwned[name] {
+			paths = append(paths, name)
+		}
+	}
+
+	sort.Strings(paths)
+
+	changed := 0
+
+	for _, path := range paths {
+		action, err := upgradeFile(path, filepath.Join(generated, filepath.FromSlash(path)), &manifest, *dryRun)
+		if err != nil {
+			return fmt.Errorf("error upgrading %s: %w", path, err)
+		}
+
+		if action != "" {
+			fmt.Println(action)
+			changed++
+		}
+	}
+
+	if changed == 0 {
+		fmt.Println("The generated files are up to date with the templates")
+	}
+
+	if *dryRun {
+		return nil
+	}
+
+	manifest.Version = currentVersion()
+	if err := writeManifest(ManifestFile, manifest); err != nil {
+		return fmt.Errorf("error updating %s: %w", ManifestFile, err)
+	}
+
+	return nil
+}
+
+// upgradeFile brings path up to date with the generated file src and
+// records its new checksum in m. It returns what it did, or "" when the
+// file was up to date.
+func upgradeFile(path, src string, m *projectManifest, dryRun bool) (string, error) {
+	name := filepath.FromSlash(path)
+	recorded, generatedBefore := m.Files[path]
+
+	updated, err := os.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+
+	sum := checksum(updated)
+
+	current, err := os.ReadFile(name)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && generatedBefore:
+		return "", nil
+	case errors.Is(err, os.ErrNotExist) && dryRun:
+		return "Would add " + path, nil
+	case errors.Is(err, os.ErrNotExist):
+		if err := copyFile(src, name); err != nil {
+			return "", err
+		}
+
+		m.Files[path] = sum
+
+		return "Added " + path, nil
+	case err != nil:
+		return "", err
+	}
+
+	currentSum := checksum(current)
+
+	switch {
+	case sum == recorded || currentSum == sum:
+		if !dryRun && generatedBefore {
+			m.Files[path] = sum
+		}
+
+		return "", nil
+	case !generatedBefore:
+		return "Skipped " + path + ", goinit did not generate it", nil
+	case dryRun && currentSum == recorded:
+		return "Would update " + path, nil
+	case dryRun:
+		return "Would merge " + path + ", it changed in the project", nil
+	}
+
+	if currentSum == recorded {
+		m.Files[path] = sum
+		return "Updated " + path, writeKeepingMode(name, updated)
+	}
+
+	if bytes.IndexByte(current, 0) >= 0 || bytes.IndexByte(updated, 0) >= 0 {
+		return "Skipped " + path + ", a binary file changed in the project", nil
+	}
+
+	// The template's version is the base of the next upgrade, whether the
+	// changes are merged or rejected.
+	m.Files[path] = sum
+
+	base, found, err := generatedVersion(path, recorded)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		var diff bytes.Buffer
+
+		// Without the base, the diff also undoes the project's changes, so
+		// it is applied by hand.
+		writeUnifiedDiff(&diff, "project/"+path, "template/"+path, splitLines(string(current)), splitLines(string(updated)), DefaultDiffContext)
+
+		if err := os.WriteFile(name+RejectExt, diff.Bytes(), 0o644); err != nil {
+			return "", err
+		}
+
+		return "Rejected " + path + ", the version goinit generated is not in the history, merge " + path + RejectExt + " by hand", nil
+	}
+
+	merged, conflicts, err := mergeFile(current, base, updated)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeKeepingMode(name, merged); err != nil {
+		return "", err
+	}
+
+	if conflicts > 0 {
+		return fmt.Sprintf("Merged %s with %d conflicts, resolve them", path, conflicts), nil
+	}
+
+	return "Merged " + path, nil
+}
+
+// writeKeepingMode replaces the content of the existing file name.
+func writeKeepingMode(name string, data []byte) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, info.Mode().Perm())
+}
+
+// generatedVersion looks through the history of path for the version with
+// the checksum goinit recorded when generating it.
+func generatedVersion(path, sum string) ([]byte, bool, error) {
+	out, err := commandOutput("", "git", "log", "--format=%H", "--", path)
+	if err != nil {
+		// A repository without commits has no history to search.
+		return nil, false, nil
+	}
+
+	for _, rev := range strings.Fields(string(out)) {
+		data, err := commandOutput("", "git", "show", rev+":"+path)
+		if err != nil {
+			continue
+		}
+
+		if checksum(data) == sum {
+			return data, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// mergeFile merges the changes from base to updated into current, marking
+// the conflicts, and returns the result and how many conflicts it has.
+func mergeFile(current, base, updated []byte) ([]byte, int, error) {
+	dir, err := os.MkdirTemp("", "goinit-merge-")
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{filepath.Join(dir, "project"), filepath.Join(dir, "generated"), filepath.Join(dir, "template")}
+	for i, data := range [][]byte{current, base, updated} {
+		if err := os.WriteFile(files[i], data, 0o644); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var out []byte
+
+	cmd, run := command(commandTimeout, "git", "merge-file", "-p", "-L", "project", "-L", "generated", "-L", "template", files[0], files[1], files[2])
+	err = run(func() (err error) {
+		out, err = cmd.Output()
+		return err
+	})
+
+	// git merge-file exits with the number of conflicts, and a negative
+	// status on errors.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return out, exitErr.ExitCode(), nil
+	}
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("error merging: %w", err)
+	}
+
+	return out, 0, nil
+}
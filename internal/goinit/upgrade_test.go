@@ -0,0 +1,238 @@
+package goinit
+
+import (
+	"flag"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// requireGit skips the test without git, which merges the upgrades.
+func requireGit(t *testing.T) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+}
+
+// chdir changes to dir for the rest of the test, as upgrade works in the
+// project's directory.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func writeTestFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestMergeFile(t *testing.T) {
+	requireGit(t)
+
+	base := []byte("one\ntwo\nthree\nfour\nfive\n")
+	current := []byte("one\ntwo changed in the project\nthree\nfour\nfive\n")
+	updated := []byte("one\ntwo\nthree\nfour\nfive changed in the template\n")
+
+	merged, conflicts, err := mergeFile(current, base, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "one\ntwo changed in the project\nthree\nfour\nfive changed in the template\n"; conflicts != 0 || string(merged) != want {
+		t.Errorf("mergeFile = %q with %d conflicts, want %q without conflicts", merged, conflicts, want)
+	}
+
+	conflicting := []byte("one\ntwo\nthree\nfour\nfive changed in the project\n")
+
+	merged, conflicts, err = mergeFile(conflicting, base, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if conflicts != 1 || !strings.Contains(string(merged), "<<<<<<< project") || !strings.Contains(string(merged), ">>>>>>> template") {
+		t.Errorf("mergeFile = %q with %d conflicts, want 1 marked conflict", merged, conflicts)
+	}
+}
+
+func TestUpgradeFile(t *testing.T) {
+	requireGit(t)
+
+	templates := t.TempDir()
+	project := t.TempDir()
+	chdir(t, project)
+
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(templates, ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	generated := "one\ntwo\nthree\nfour\nfive\n"
+	updated := "one\ntwo\nthree\nfour\nfive changed in the template\n"
+
+	for _, name := range []string{"unchanged.txt", "changed.txt", "untracked.txt", "removed.txt", "added.txt", "current.txt"} {
+		writeTestFile(t, filepath.Join(templates, name), updated)
+	}
+
+	for _, name := range []string{"unchanged.txt", "changed.txt", "untracked.txt"} {
+		writeTestFile(t, name, generated)
+	}
+
+	writeTestFile(t, "current.txt", updated)
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "changed.txt"},
+		{"-c", "user.name=goinit", "-c", "user.email=goinit@example.com", "commit", "-q", "-m", "generated"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	// The project changed these since goinit generated them.
+	writeTestFile(t, "changed.txt", "one\ntwo changed in the project\nthree\nfour\nfive\n")
+	writeTestFile(t, "untracked.txt", "one\ntwo changed in the project\nthree\nfour\nfive\n")
+
+	m := &projectManifest{Files: map[string]string{
+		"unchanged.txt": checksum([]byte(generated)),
+		"changed.txt":   checksum([]byte(generated)),
+		"untracked.txt": checksum([]byte(generated)),
+		"removed.txt":   checksum([]byte(generated)),
+		"current.txt":   checksum([]byte(generated)),
+	}}
+
+	tests := []struct {
+		path    string
+		dryRun  string
+		message string
+		content string
+	}{
+		{"unchanged.txt", "Would update unchanged.txt", "Updated unchanged.txt", updated},
+		{"changed.txt", "Would merge changed.txt, it changed in the project", "Merged changed.txt", "one\ntwo changed in the project\nthree\nfour\nfive changed in the template\n"},
+		{"untracked.txt", "Would merge untracked.txt, it changed in the project", "Rejected untracked.txt, the version goinit generated is not in the history, merge untracked.txt.rej by hand", "one\ntwo changed in the project\nthree\nfour\nfive\n"},
+		{"added.txt", "Would add added.txt", "Added added.txt", updated},
+		{"current.txt", "", "", updated},
+	}
+
+	for _, tt := range tests {
+		src := filepath.Join(templates, tt.path)
+
+		if got, err := upgradeFile(tt.path, src, m, true); err != nil || got != tt.dryRun {
+			t.Errorf("dry run upgradeFile(%q) = %q, %v, want %q", tt.path, got, err, tt.dryRun)
+		}
+
+		if got, err := upgradeFile(tt.path, src, m, false); err != nil || got != tt.message {
+			t.Errorf("upgradeFile(%q) = %q, %v, want %q", tt.path, got, err, tt.message)
+		}
+
+		if got := readTestFile(t, tt.path); got != tt.content {
+			t.Errorf("%s = %q after upgrading, want %q", tt.path, got, tt.content)
+		}
+
+		if got, want := m.Files[tt.path], checksum([]byte(updated)); got != want {
+			t.Errorf("recorded checksum of %s = %s, want the template's %s", tt.path, got, want)
+		}
+	}
+
+	if rej := readTestFile(t, "untracked.txt"+RejectExt); !strings.Contains(rej, "+five changed in the template") {
+		t.Errorf("untracked.txt%s = %q, want the template's changes", RejectExt, rej)
+	}
+
+	// A generated file the project removed stays removed.
+	if got, err := upgradeFile("removed.txt", filepath.Join(templates, "removed.txt"), m, false); err != nil || got != "" {
+		t.Errorf("upgradeFile of a removed file = %q, %v, want nothing done", got, err)
+	}
+
+	if _, err := os.Stat("removed.txt"); !os.IsNotExist(err) {
+		t.Errorf("upgradeFile recreated removed.txt: %v", err)
+	}
+}
+
+// TestRecordedOptions regenerates projects from the options their manifest
+// records, as diff and upgrade do.
+func TestRecordedOptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating projects is slow")
+	}
+
+	requireGit(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"defaults", nil},
+		{"host-gitlab", []string{"-host=gitlab.com"}},
+		{"module", []string{"-module=example.com/team/snapshot"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			isolateSnapshotEnv(t)
+
+			var opts options
+
+			set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+			set.SetOutput(io.Discard)
+			registerFlags(set, &opts)
+
+			if err := set.Parse(append([]string{"-" + ProjectNameFlag + "=" + SnapshotProject}, tt.args...)); err != nil {
+				t.Fatal(err)
+			}
+
+			opts.args = changedArgs(set)
+
+			if err := opts.validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := generateIn(rootCtx, wd, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			chdir(t, filepath.Join(wd, SnapshotProject))
+
+			if err := projectDiff(nil); err != nil {
+				t.Errorf("diff: %v", err)
+			}
+
+			if err := upgrade([]string{"-dry-run"}); err != nil {
+				t.Errorf("upgrade -dry-run: %v", err)
+			}
+		})
+	}
+}
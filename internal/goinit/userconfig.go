@@ -0,0 +1,91 @@
+package goinit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	UserConfigFile  = "config.yaml"
+	ModulePrefixKey = "module_prefix"
+	AuthorKey       = "author"
+)
+
+// userConfiguration holds the defaults of the user's config file.
+type userConfiguration struct {
+	// Path is the config file read, also when it does not exist.
+	Path string
+	// ModulePrefix is prepended to the project name for the module path,
+	// in place of the host and the user looked up for it.
+	ModulePrefix string
+	// Author replaces the git user as the author of the project.
+	Author string
+	// Options are defaults of the generation options, which options given
+	// on the command line or in an answers file override.
+	Options []answer
+}
+
+// userConfig is read once at startup, before running any command.
+var userConfig userConfiguration
+
+// loadUserConfig reads the config file in goinit's configuration
+// directory. Not having one is not an error.
+func loadUserConfig() (userConfiguration, error) {
+	dir, err := configDir()
+	if err != nil {
+		return userConfiguration{}, err
+	}
+
+	c := userConfiguration{Path: filepath.Join(dir, UserConfigFile)}
+
+	lines, err := readLines(c.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+
+	if err != nil {
+		return c, fmt.Errorf("error reading config: %w", err)
+	}
+
+	answers, err := parseAnswers(c.Path, lines)
+	if err != nil {
+		return c, err
+	}
+
+	// The options are checked against the generation flags up front, so a
+	// typo fails every command rather than only new.
+	set := flag.NewFlagSet("goinit", flag.ContinueOnError)
+	registerFlags(set, &options{})
+
+	for _, a := range answers {
+		switch a.Key {
+		case ModulePrefixKey:
+			c.ModulePrefix = strings.TrimSuffix(a.Value, "/")
+		case AuthorKey:
+			c.Author = a.Value
+		case ProjectNameAnswer, ProjectNameFlag:
+			return c, fmt.Errorf("%s:%d: the project name cannot have a default", c.Path, a.Line)
+		default:
+			c.Options = append(c.Options, a)
+		}
+	}
+
+	if err := setAnswers(set, c.Path, c.Options, nil); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// applyUserConfig sets the options of fs the config file has defaults for,
+// leaving out the ones already given.
+func applyUserConfig(fs *flag.FlagSet) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	return setAnswers(fs, userConfig.Path, userConfig.Options, given)
+}
@@ -0,0 +1,78 @@
+package goinit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const SetFlag = "set"
+
+// templateVars are the variables of the project being generated, set with
+// -set. Every rendered template reads them with the var and vars functions.
+var templateVars = varsFlag{}
+
+// varsFlag collects repeated -set key=value flags. Like helm's --set, one
+// flag can also set several variables separated by commas.
+type varsFlag map[string]string
+
+func (v varsFlag) String() string {
+	pairs := make([]string, 0, len(v))
+	for key, value := range v {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (v varsFlag) Set(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return errors.New("expected key=value")
+		}
+
+		v[key] = value
+	}
+
+	return nil
+}
+
+// templateFuncs are available to every rendered template:
+//
+//	{{ var "team" }}             the variable, or "" when not set
+//	{{ required "team" }}        the variable, failing when not set
+//	{{ range $k, $v := vars }}   all variables
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"var":      func(key string) string { return templateVars[key] },
+		"required": requireVar,
+		"vars": func() map[string]string {
+			vars := make(map[string]string, len(templateVars))
+			for key, value := range templateVars {
+				vars[key] = value
+			}
+
+			return vars
+		},
+	}
+}
+
+// requireVar fails rendering when a template needs a variable that was not
+// set, naming the flag to set it with.
+func requireVar(key string) (string, error) {
+	value, ok := templateVars[key]
+	if !ok {
+		return "", fmt.Errorf("variable %q is not set, pass -set %s=<value>", key, key)
+	}
+
+	return value, nil
+}
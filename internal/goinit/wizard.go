@@ -0,0 +1,180 @@
+package goinit
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const InteractiveFlag = "i"
+
+// prompter asks questions on a terminal, offering a default that an empty
+// answer accepts.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		fmt.Fprintln(p.out)
+		return "", fmt.Errorf("no answer to %q: %w", question, err)
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+
+	return def, nil
+}
+
+// choose asks until the answer is one of choices.
+func (p prompter) choose(question string, choices []string, def string) (string, error) {
+	for {
+		answer, err := p.ask(question+" ("+strings.Join(choices, ", ")+")", def)
+		if err != nil {
+			return "", err
+		}
+
+		for _, c := range choices {
+			if strings.EqualFold(answer, c) {
+				return c, nil
+			}
+		}
+
+		fmt.Fprintf(p.out, "Choose one of %s\n", strings.Join(choices, ", "))
+	}
+}
+
+func (p prompter) confirm(question string, def bool) (bool, error) {
+	hint := "Y/n"
+	if !def {
+		hint = "y/N"
+	}
+
+	for {
+		answer, err := p.ask(question+" ("+hint+")", "")
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+
+		fmt.Fprintln(p.out, "Answer y or n")
+	}
+}
+
+// runWizard asks for the project name, module path, license and components,
+// offering the values of fs as defaults, and sets the answers on fs.
+func runWizard(in io.Reader, out io.Writer, fs *flag.FlagSet, opts *options) error {
+	p := prompter{in: bufio.NewReader(in), out: out}
+
+	var name string
+
+	for {
+		var err error
+		if name, err = p.ask("Project name", opts.projectName); err != nil {
+			return err
+		}
+
+		err = checkProjectName(name)
+		if err == nil {
+			break
+		}
+
+		fmt.Fprintln(out, err)
+	}
+
+	if err := fs.Set(ProjectNameFlag, name); err != nil {
+		return err
+	}
+
+	derived := modulePath(opts.host, name)
+
+	def := opts.module
+	if def == "" {
+		def = derived
+	}
+
+	var module string
+
+	for {
+		var err error
+		if module, err = p.ask("Module path", def); err != nil {
+			return err
+		}
+
+		if err := checkModulePath(module); err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+
+		break
+	}
+
+	// A derived path is left derived, as when no -module is given.
+	if module != derived {
+		if err := fs.Set("module", module); err != nil {
+			return err
+		}
+	}
+
+	license := opts.license
+	if license == "" {
+		license = "none"
+	}
+
+	license, err := p.choose("License", []string{"none", "mit", "apache-2.0", "bsd-3-clause"}, license)
+	if err != nil {
+		return err
+	}
+
+	if license == "none" {
+		license = ""
+	}
+
+	if err := fs.Set("license", license); err != nil {
+		return err
+	}
+
+	components := []struct {
+		name     string
+		question string
+	}{
+		{ComponentMakefile, "Generate a Makefile?"},
+		{ComponentCI, "Generate the GitHub Actions CI and release workflows?"},
+		{ComponentHooks, "Install the pre-commit hook?"},
+	}
+
+	var skip []string
+
+	for _, c := range components {
+		include, err := p.confirm(c.question, !opts.skips(c.name))
+		if err != nil {
+			return err
+		}
+
+		if !include {
+			skip = append(skip, c.name)
+		}
+	}
+
+	return fs.Set("skip", strings.Join(skip, ","))
+}
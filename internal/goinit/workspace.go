@@ -0,0 +1,120 @@
+package goinit
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	WorkspaceFlag  = "workspace"
+	GoWorkFile     = "go.work"
+	GoWorkTemplate = "templates/workspace/go.work.tmpl"
+)
+
+// createWorkspace makes the project the root module of a go.work, which
+// goinit add module adds the other modules of the repository to. The
+// Makefile and CI workflow run on every module of it.
+func createWorkspace(dir string, ctx projectContext) error {
+	return renderFile(dir, GoWorkFile, templatesFS, GoWorkTemplate, ctx)
+}
+
+// addModule creates a module with a starter command in the directory
+// rel of the workspace in the working directory, named after the root
+// module, and adds it to the go.work.
+func addModule(rel string) error {
+	if !exists(GoWorkFile) {
+		return fmt.Errorf("no %s in the working directory, generate the project with -%s or run go work init first", GoWorkFile, WorkspaceFlag)
+	}
+
+	root := goModDirective("go.mod", "module")
+	if root == "" {
+		return errors.New("the workspace has no root module to name the module after")
+	}
+
+	rel = path.Clean(filepath.ToSlash(rel))
+	if path.IsAbs(rel) || rel == "." || strings.HasPrefix(rel, "../") || rel == ".." {
+		return fmt.Errorf("%s is not a directory inside the workspace", rel)
+	}
+
+	if exists(rel) {
+		return fmt.Errorf("%s already exists, nothing was added", rel)
+	}
+
+	modulePath := root + "/" + rel
+	if err := checkModulePath(modulePath); err != nil {
+		return err
+	}
+
+	dir := filepath.FromSlash(rel)
+
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+
+	if err := goModInit(dir, modulePath); err != nil {
+		return fmt.Errorf("error initializing module %s: %w", modulePath, err)
+	}
+
+	// The modules of a workspace share the Go version of the root module.
+	if version := goModDirective("go.mod", "go"); version != "" {
+		if err := runCommand(dir, "go", "mod", "edit", "-go="+version); err != nil {
+			return fmt.Errorf("error setting the go directive: %w", err)
+		}
+	}
+
+	opts := options{projectName: path.Base(rel), module: modulePath}
+	if err := createStarter(dir, newProjectContext(dir, opts)); err != nil {
+		return err
+	}
+
+	if err := runCommand(".", "go", "work", "use", "./"+rel); err != nil {
+		return fmt.Errorf("error adding %s to %s: %w", rel, GoWorkFile, err)
+	}
+
+	fmt.Printf("Added module %s in %s\n", modulePath, rel)
+
+	if !exists(ManifestFile) {
+		return nil
+	}
+
+	return recordModule(dir)
+}
+
+// recordModule adds the files of the module in dir to the project's
+// manifest, so undo removes them too, and the go.work it was added to.
+func recordModule(dir string) error {
+	manifest, err := readManifest(ManifestFile)
+	if err != nil {
+		return err
+	}
+
+	names, err := walkFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	files := []string{GoWorkFile}
+	for name := range names {
+		files = append(files, path.Join(filepath.ToSlash(dir), name))
+	}
+
+	for _, file := range files {
+		if _, ok := manifest.Files[file]; !ok && file == GoWorkFile {
+			continue
+		}
+
+		if manifest.Files[file], err = fileChecksum(filepath.FromSlash(file)); err != nil {
+			return fmt.Errorf("error reading %s: %w", file, err)
+		}
+	}
+
+	return writeManifest(ManifestFile, manifest)
+}
+
+// isWorkspace reports whether the project in dir is the root of a go.work.
+func isWorkspace(dir string) bool {
+	return exists(filepath.Join(dir, GoWorkFile))
+}
@@ -0,0 +1,148 @@
+// Package scaffold generates the Go projects of goinit. Tools embedding
+// goinit create a Scaffolder rather than running the goinit command:
+//
+//	err := scaffold.New(scaffold.Options{
+//		Name:   "service",
+//		Module: "example.com/team/service",
+//		Type:   "api",
+//		Docker: true,
+//		CI:     "gitlab",
+//	}).Run(ctx)
+package scaffold
+
+import (
+	"context"
+
+	"github.com/AlexEkdahl/goinit/internal/goinit"
+)
+
+// Options are the options of a generated project, one field for each flag
+// of goinit new, which documents their values. Zero values leave the
+// flag's default.
+type Options struct {
+	// Name is the project's name and the directory it is created in.
+	Name string
+	// Dir is the directory the project is created in, the working
+	// directory when empty.
+	Dir string
+	// Module is the module path of go.mod, derived from Name when empty.
+	Module string
+	// Type is the project archetype: cli, lib, api, grpc, worker or
+	// openapi.
+	Type string
+	// License is the generated LICENSE: mit, apache-2.0 or bsd-3-clause.
+	License string
+	// Template is a directory or git repository of templates overriding
+	// the embedded ones.
+	Template string
+	// Vars are the template variables.
+	Vars map[string]string
+	// Layout is the project layout: operator, tf-provider, github-app,
+	// bot, cronjob, desktop, mobile, mcp or ssh-app.
+	Layout string
+	// Router is the router of the api Type: stdlib, chi, echo or gin.
+	Router string
+	// Platform is the chat platform of the bot Layout: slack or discord.
+	Platform string
+	// Framework is the GUI toolkit of the desktop Layout: fyne or wails.
+	Framework string
+	// FlagsPackage generates a CLI main.go using stdlib, pflag, urfave or
+	// kong.
+	FlagsPackage string
+	// DI generates a server wired with wire or fx.
+	DI string
+	// Mocks generates an example interface and mock with mockery or
+	// mockgen.
+	Mocks string
+	// DB is the database of the api Type: postgres, sqlite or mysql.
+	DB string
+	// Migrations is the migration tool of DB: goose or golang-migrate.
+	Migrations string
+	// GoVersion is the Go version of go.mod, CI, releases and the
+	// Dockerfile, the installed toolchain's when empty.
+	GoVersion string
+	// Host is the forge hosting the project, such as gitlab.com.
+	Host string
+	// CI is the CI provider: github, gitlab, bitbucket, circleci or none.
+	CI string
+	// Lint is the golangci-lint preset: strict, standard, minimal or none.
+	Lint string
+	// Editor is the editor settings generated: vscode or none.
+	Editor string
+	// Hooks is the pre-commit hook: script, pre-commit-framework or
+	// lefthook.
+	Hooks string
+	// TaskRunner is the task runner: make, task or just.
+	TaskRunner string
+	// Skip are the components left out: makefile, ci and hooks.
+	Skip []string
+	// BuildTargets are the GOOS/GOARCH pairs built, such as linux/amd64.
+	BuildTargets []string
+	// Release is the release automation: goreleaser or semantic-release.
+	Release string
+	// ReleaseNotes is the release notes generator: goreleaser or drafter.
+	ReleaseNotes string
+	// Changelog is the changelog tool: release-please, git-cliff or none.
+	Changelog string
+	// Registry publishes the release to artifactory or nexus.
+	Registry string
+	// BrewTap publishes a Homebrew formula to the owner/repo tap.
+	BrewTap string
+	// Branch is the branch Commit creates.
+	Branch string
+	// ComposeServices are the services Compose runs next to the app:
+	// postgres and redis.
+	ComposeServices []string
+
+	// The switches generate what the flag they are named after does, as
+	// Docker does -docker.
+	RateLimit    bool
+	CORS         bool
+	Assets       bool
+	I18n         bool
+	Automation   bool
+	Labels       bool
+	Protect      bool
+	NoGit        bool
+	Commit       bool
+	CreateRemote bool
+	Private      bool
+	Push         bool
+	Provenance   bool
+	Buildx       bool
+	Docker       bool
+	Trivy        bool
+	AUR          bool
+	Debian       bool
+	RPM          bool
+	Chocolatey   bool
+	DockerImages bool
+	Nfpm         bool
+	SupplyChain  bool
+	Tools        bool
+	Sops         bool
+	Environments bool
+	K8s          bool
+	Compose      bool
+	Devcontainer bool
+	NoReadme     bool
+	Workspace    bool
+	Community    bool
+}
+
+// Scaffolder generates the project of its options.
+type Scaffolder struct {
+	opts Options
+}
+
+// New returns a Scaffolder generating the project of opts.
+func New(opts Options) *Scaffolder {
+	return &Scaffolder{opts: opts}
+}
+
+// Run generates the project, with its git and go commands running under
+// ctx. Runs in one process take turns, as they share the templates and
+// the template variables.
+func (s *Scaffolder) Run(ctx context.Context) error {
+	return goinit.Generate(ctx, goinit.Options(s.opts))
+}